@@ -186,6 +186,7 @@ func main() {
 	// You can use empty structs instead of booleans, because they use zero bytes
 	// However, you rely on the comma ok idiom to check for the existence of values
 	// This is probably not necessary unless you have a lot of values
+	// With generics (chapter 15) this becomes a reusable Set[T], see 15-generics/set.go
 
 	fmt.Println("-- Structs --")
 	type person struct {
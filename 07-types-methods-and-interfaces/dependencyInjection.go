@@ -4,6 +4,8 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"reflect"
+	"strings"
 )
 
 // -- Controller --
@@ -105,13 +107,199 @@ func (lg LoggerAdapter) Log(message string) {
 	lg(message)
 }
 
+// -- Container --
+// Wiring by hand gets tedious once there are dozens of constructors, so the Container
+// uses reflection to read the parameter types of each registered constructor and
+// resolve them from the other constructors it knows about.
+type Container struct {
+	providers map[reflect.Type]reflect.Value
+	bindings  map[reflect.Type]reflect.Type
+	instances map[reflect.Type]reflect.Value
+}
+
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+var (
+	ErrMissingProvider = errors.New("missing provider")
+	ErrDependencyCycle = errors.New("dependency cycle")
+)
+
+// DependencyError reports a missing provider, a cycle or a failed constructor along
+// with the chain of types that was being resolved when it happened
+type DependencyError struct {
+	Chain []reflect.Type
+	Err   error
+}
+
+func (de DependencyError) Error() string {
+	names := make([]string, len(de.Chain))
+	for i, t := range de.Chain {
+		names[i] = t.String()
+	}
+	return fmt.Sprintf("%v: %s", de.Err, strings.Join(names, " -> "))
+}
+
+// Unwrap lets callers check errors.Is(err, ErrMissingProvider) or errors.Is(err, ErrDependencyCycle)
+func (de DependencyError) Unwrap() error {
+	return de.Err
+}
+
+func NewContainer() *Container {
+	return &Container{
+		providers: map[reflect.Type]reflect.Value{},
+		bindings:  map[reflect.Type]reflect.Type{},
+		instances: map[reflect.Type]reflect.Value{},
+	}
+}
+
+// Provide registers a constructor. It must be a function that returns a single value,
+// or a value and an error. Its parameters are resolved from the container.
+func (c *Container) Provide(constructor interface{}) error {
+	if constructor == nil {
+		return errors.New("constructor must not be nil")
+	}
+	cv := reflect.ValueOf(constructor)
+	ct := cv.Type()
+	if ct.Kind() != reflect.Func {
+		return fmt.Errorf("constructor must be a function, got %v", ct)
+	}
+	if cv.IsNil() {
+		return fmt.Errorf("constructor %v must not be nil", ct)
+	}
+	if ct.IsVariadic() {
+		// Call would pass the variadic parameter as a single slice
+		return fmt.Errorf("constructor %v must not be variadic", ct)
+	}
+	if ct.NumOut() == 0 || ct.NumOut() > 2 || (ct.NumOut() == 2 && ct.Out(1) != errorType) {
+		return fmt.Errorf("constructor %v must return a value or a value and an error", ct)
+	}
+	out := ct.Out(0)
+	if _, ok := c.providers[out]; ok {
+		return fmt.Errorf("provider for %v already registered", out)
+	}
+	c.providers[out] = cv
+	return nil
+}
+
+// Bind maps an interface to the concrete type that should be used for it.
+// Both are passed as nil pointers, e.g. Bind((*DataStore)(nil), (*SimpleDataStore)(nil)).
+// The pointer is removed to get the type, so when the constructor returns a *T, because
+// T has pointer receiver methods, impl must be a pointer to that pointer: (**T)(nil).
+func (c *Container) Bind(iface interface{}, impl interface{}) error {
+	it := reflect.TypeOf(iface)
+	ct := reflect.TypeOf(impl)
+	if it == nil || it.Kind() != reflect.Ptr || it.Elem().Kind() != reflect.Interface {
+		return errors.New("iface must be a pointer to an interface")
+	}
+	if ct == nil || ct.Kind() != reflect.Ptr {
+		return errors.New("impl must be a pointer to the concrete type")
+	}
+	it, ct = it.Elem(), ct.Elem()
+	if !ct.Implements(it) {
+		return fmt.Errorf("%v does not implement %v", ct, it)
+	}
+	c.bindings[it] = ct
+	return nil
+}
+
+// Resolve builds the value pointed to by target, along with all of its dependencies.
+// Every type is only built once and then shared.
+func (c *Container) Resolve(target interface{}) error {
+	tv := reflect.ValueOf(target)
+	if tv.Kind() != reflect.Ptr || tv.IsNil() {
+		return errors.New("target must be a non-nil pointer")
+	}
+	v, err := c.resolve(tv.Type().Elem(), nil)
+	if err != nil {
+		return err
+	}
+	tv.Elem().Set(v)
+	return nil
+}
+
+func (c *Container) resolve(t reflect.Type, chain []reflect.Type) (reflect.Value, error) {
+	if v, ok := c.instances[t]; ok {
+		return v, nil
+	}
+	for _, seen := range chain {
+		if seen == t {
+			return reflect.Value{}, DependencyError{
+				Chain: appendType(chain, t),
+				Err:   ErrDependencyCycle,
+			}
+		}
+	}
+	chain = appendType(chain, t)
+
+	var v reflect.Value
+	if provider, ok := c.providers[t]; ok {
+		args := make([]reflect.Value, provider.Type().NumIn())
+		for i := range args {
+			arg, err := c.resolve(provider.Type().In(i), chain)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			args[i] = arg
+		}
+		out := provider.Call(args)
+		if len(out) == 2 && !out[1].IsNil() {
+			return reflect.Value{}, DependencyError{
+				Chain: chain,
+				Err:   fmt.Errorf("constructor failed: %w", out[1].Interface().(error)),
+			}
+		}
+		v = out[0]
+	} else if impl, ok := c.bindings[t]; ok {
+		iv, err := c.resolve(impl, chain)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		// Convert the concrete value so it can be set into a variable of the interface type
+		v = reflect.New(t).Elem()
+		v.Set(iv)
+	} else {
+		return reflect.Value{}, DependencyError{
+			Chain: chain,
+			Err:   ErrMissingProvider,
+		}
+	}
+	c.instances[t] = v
+	return v, nil
+}
+
+// appendType always copies, so sibling dependencies don't share the backing array of the chain
+func appendType(chain []reflect.Type, t reflect.Type) []reflect.Type {
+	out := make([]reflect.Type, len(chain), len(chain)+1)
+	copy(out, chain)
+	return append(out, t)
+}
+
+// -- End - Container --
+
 // Main
 func main() {
-	l := LoggerAdapter(myLogOutput)
-	ds := NewSimpleDataStore()
-	logic := NewSimpleLogic(l, ds)
-	c := NewController(l, logic)
-	http.HandleFunc("/hello", c.Greet)
+	c := NewContainer()
+	// errors.Join returns nil when all of them are nil
+	err := errors.Join(
+		c.Provide(func() LoggerAdapter { return LoggerAdapter(myLogOutput) }),
+		c.Provide(NewSimpleDataStore),
+		c.Provide(NewSimpleLogic),
+		c.Provide(NewController),
+		c.Bind((*Logger)(nil), (*LoggerAdapter)(nil)),
+		c.Bind((*DataStore)(nil), (*SimpleDataStore)(nil)),
+		c.Bind((*Logic)(nil), (*SimpleLogic)(nil)),
+	)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+
+	var ctrl Controller
+	if err := c.Resolve(&ctrl); err != nil {
+		fmt.Println(err)
+		return
+	}
+	http.HandleFunc("/hello", ctrl.Greet)
 	fmt.Println("Listening to port 8000...")
 	http.ListenAndServe(":8000", nil)
 }
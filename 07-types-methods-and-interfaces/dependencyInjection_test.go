@@ -0,0 +1,168 @@
+package main
+
+// This folder has more than one main, so run the tests with the file they cover:
+// `$ go test ./07-types-methods-and-interfaces/dependencyInjection.go ./07-types-methods-and-interfaces/dependencyInjection_test.go`
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type cycleA struct{}
+type cycleB struct{}
+type cycleC struct{}
+
+func TestContainerResolve(t *testing.T) {
+	c := NewContainer()
+	calls := 0
+	err := errors.Join(
+		c.Provide(func() LoggerAdapter {
+			calls++
+			return LoggerAdapter(func(string) {})
+		}),
+		c.Provide(NewSimpleDataStore),
+		c.Provide(NewSimpleLogic),
+		c.Provide(NewController),
+		c.Bind((*Logger)(nil), (*LoggerAdapter)(nil)),
+		c.Bind((*DataStore)(nil), (*SimpleDataStore)(nil)),
+		c.Bind((*Logic)(nil), (*SimpleLogic)(nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ctrl Controller
+	if err := c.Resolve(&ctrl); err != nil {
+		t.Fatal(err)
+	}
+	message, err := ctrl.logic.SayHello("1")
+	if err != nil || message != "Hello Fred Astaire\n" {
+		t.Errorf("unexpected greeting %q, %v", message, err)
+	}
+	// The logger is shared by the Controller and the SimpleLogic
+	if calls != 1 {
+		t.Errorf("expected the logger to be built once, got %d", calls)
+	}
+}
+
+type counter struct{ n int }
+
+func (c *counter) Log(message string) {
+	c.n++
+}
+
+func TestContainerBindPointer(t *testing.T) {
+	c := NewContainer()
+	// Only *counter implements Logger, so the binding needs a pointer to *counter
+	err := errors.Join(
+		c.Provide(func() *counter { return &counter{} }),
+		c.Bind((*Logger)(nil), (**counter)(nil)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var l Logger
+	if err := c.Resolve(&l); err != nil {
+		t.Fatal(err)
+	}
+	l.Log("hello")
+	var cnt *counter
+	if err := c.Resolve(&cnt); err != nil {
+		t.Fatal(err)
+	}
+	if cnt.n != 1 {
+		t.Errorf("expected the Logger to be the shared *counter, got %d calls", cnt.n)
+	}
+}
+
+func TestContainerErrors(t *testing.T) {
+	typeOf := func(v interface{}) reflect.Type {
+		return reflect.TypeOf(v).Elem()
+	}
+	data := []struct {
+		name   string
+		setup  func(c *Container) error
+		target interface{}
+		err    error
+		errMsg string
+	}{
+		{"missing_provider", func(c *Container) error {
+			return c.Provide(NewController)
+		}, &Controller{}, ErrMissingProvider, "missing provider: main.Controller -> main.Logger"},
+		{"missing_binding", func(c *Container) error {
+			return errors.Join(
+				c.Provide(NewSimpleLogic),
+				c.Provide(NewController),
+				c.Provide(func() LoggerAdapter { return nil }),
+				c.Bind((*Logger)(nil), (*LoggerAdapter)(nil)),
+				c.Bind((*Logic)(nil), (*SimpleLogic)(nil)),
+			)
+		}, &Controller{}, ErrMissingProvider, "missing provider: main.Controller -> main.Logic -> main.SimpleLogic -> main.DataStore"},
+		{"cycle", func(c *Container) error {
+			return errors.Join(
+				c.Provide(func(cycleB) cycleA { return cycleA{} }),
+				c.Provide(func(cycleC) cycleB { return cycleB{} }),
+				c.Provide(func(cycleA) cycleC { return cycleC{} }),
+			)
+		}, &cycleA{}, ErrDependencyCycle, "dependency cycle: main.cycleA -> main.cycleB -> main.cycleC -> main.cycleA"},
+		{"constructor_error", func(c *Container) error {
+			return errors.Join(
+				c.Provide(func(cycleB) (cycleA, error) { return cycleA{}, errors.New("boom") }),
+				c.Provide(func() cycleB { return cycleB{} }),
+			)
+		}, &cycleA{}, nil, "constructor failed: boom: main.cycleA"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			c := NewContainer()
+			if err := d.setup(c); err != nil {
+				t.Fatal(err)
+			}
+			err := c.Resolve(d.target)
+			var de DependencyError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected a DependencyError, got %v", err)
+			}
+			if d.err != nil && !errors.Is(err, d.err) {
+				t.Errorf("expected %v, got %v", d.err, err)
+			}
+			if err.Error() != d.errMsg {
+				t.Errorf("expected `%s`, got `%s`", d.errMsg, err.Error())
+			}
+			if de.Chain[0] != typeOf(d.target) {
+				t.Errorf("expected the chain to start at %v, got %v", typeOf(d.target), de.Chain)
+			}
+		})
+	}
+}
+
+func TestContainerProvideErrors(t *testing.T) {
+	c := NewContainer()
+	data := []struct {
+		name        string
+		constructor interface{}
+	}{
+		{"nil", nil},
+		{"nil_function", (func() cycleA)(nil)},
+		{"not_a_function", 42},
+		{"variadic", func(names ...string) cycleA { return cycleA{} }},
+		{"no_result", func() {}},
+		{"second_result_not_error", func() (cycleA, int) { return cycleA{}, 0 }},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			if err := c.Provide(d.constructor); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+	if err := c.Provide(func() cycleB { return cycleB{} }); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Provide(func() cycleB { return cycleB{} }); err == nil {
+		t.Error("expected an error for a second provider of the same type")
+	}
+	if err := c.Bind((*Logger)(nil), (*cycleB)(nil)); err == nil {
+		t.Error("expected an error binding a type that doesn't implement the interface")
+	}
+}
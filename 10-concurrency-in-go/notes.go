@@ -145,7 +145,7 @@ func main() {
 	// ToDo: document for-select
 	// ToDo: implement input listener and return fibonacci
 	// ToDo: implement parallel fetches
-	// ToDo: implement map-reduce with benchmark to compare it to sequential processing
+	// Map-reduce with a benchmark against sequential processing: see ParallelMap in 15-generics/parallel.go
 }
 
 func countTo(max int) <-chan int {
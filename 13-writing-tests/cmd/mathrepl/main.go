@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dahc36/learning-go/13-writing-tests/solver"
+)
+
+const help = `Enter an expression like 2 * (3 + 4), or assign one with x = 3 ^ 2.
+Constants: pi, e. Functions: sqrt, sin, cos, tan, exp, log, abs, pow, min, max.
+Commands:
+  :vars     list the variables
+  :d expr   show the derivative of expr with respect to x
+  :history  list the previous expressions
+  !n        run expression n from the history again, !! runs the last one
+  :reset    forget all the variables
+  :help     show this help
+  :quit     exit, as does Ctrl+D`
+
+// An interactive calculator on top of solver.Session: `$ go run ./13-writing-tests/cmd/mathrepl`
+func main() {
+	home, _ := os.UserHomeDir()
+	historyFile := flag.String("history", filepath.Join(home, ".mathrepl_history"),
+		"file to keep the history in between runs, empty to keep it in memory only")
+	flag.Parse()
+
+	r := repl{session: solver.NewSession(), out: os.Stdout}
+	if *historyFile != "" {
+		if err := r.loadHistory(*historyFile); err != nil {
+			fmt.Fprintln(os.Stderr, "could not load the history:", err)
+		}
+		f, err := os.OpenFile(*historyFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "could not save the history:", err)
+		} else {
+			defer f.Close()
+			r.historyOut = f
+		}
+	}
+
+	fmt.Fprintln(r.out, "Type :help for help")
+	lr := solver.NewLineReader(os.Stdin, 0)
+	for {
+		fmt.Fprint(r.out, "> ")
+		line, _, err := lr.Next()
+		if err == io.EOF {
+			fmt.Fprintln(r.out)
+			return
+		}
+		if err != nil {
+			fmt.Fprintln(r.out, err)
+			if errors.Is(err, solver.ErrLineTooLong) {
+				continue
+			}
+			os.Exit(1)
+		}
+		if !r.run(line) {
+			return
+		}
+	}
+}
+
+type repl struct {
+	session    *solver.Session
+	history    []string
+	historyOut io.Writer
+	out        io.Writer
+}
+
+func (r *repl) loadHistory(path string) error {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	lr := solver.NewLineReader(f, 0)
+	for {
+		line, _, err := lr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		r.history = append(r.history, line)
+	}
+}
+
+// run handles a line of input, it returns false when the REPL should stop
+func (r *repl) run(line string) bool {
+	switch line {
+	case ":quit", ":q":
+		return false
+	case ":help":
+		fmt.Fprintln(r.out, help)
+		return true
+	case ":vars":
+		for _, name := range r.session.Names() {
+			v, _ := r.session.Var(name)
+			fmt.Fprintf(r.out, "%s = %s\n", name, strconv.FormatFloat(v, 'g', -1, 64))
+		}
+		return true
+	case ":history":
+		for i, expression := range r.history {
+			fmt.Fprintf(r.out, "%4d  %s\n", i+1, expression)
+		}
+		return true
+	case ":reset":
+		r.session.Reset()
+		return true
+	}
+	if expression, ok := strings.CutPrefix(line, ":d "); ok {
+		r.derive(expression)
+		return true
+	}
+	if strings.HasPrefix(line, "!") {
+		expression, ok := r.recall(line)
+		if !ok {
+			fmt.Fprintf(r.out, "no history entry %s\n", line)
+			return true
+		}
+		fmt.Fprintln(r.out, expression)
+		line = expression
+	} else if strings.HasPrefix(line, ":") {
+		fmt.Fprintf(r.out, "unknown command %s, type :help for help\n", line)
+		return true
+	}
+
+	r.history = append(r.history, line)
+	if r.historyOut != nil {
+		fmt.Fprintln(r.historyOut, line)
+	}
+	result, err := r.session.Resolve(context.Background(), line)
+	if err != nil {
+		r.printError(err)
+		return true
+	}
+	fmt.Fprintln(r.out, strconv.FormatFloat(result, 'g', -1, 64))
+	return true
+}
+
+func (r *repl) derive(expression string) {
+	n, err := solver.Parse(expression)
+	if err != nil {
+		r.printError(err)
+		return
+	}
+	d, err := solver.Derive(n, "x")
+	if err != nil {
+		r.printError(err)
+		return
+	}
+	fmt.Fprintln(r.out, d)
+}
+
+// recall finds the expression for !n or !!
+func (r *repl) recall(line string) (string, bool) {
+	if line == "!!" {
+		if len(r.history) == 0 {
+			return "", false
+		}
+		return r.history[len(r.history)-1], true
+	}
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n < 1 || n > len(r.history) {
+		return "", false
+	}
+	return r.history[n-1], true
+}
+
+// printError points at the position of the error under the expression the user typed
+func (r *repl) printError(err error) {
+	pos := -1
+	var se solver.SyntaxError
+	var ee solver.EvalError
+	switch {
+	case errors.As(err, &se):
+		pos, err = se.Pos, se
+	case errors.As(err, &ee):
+		pos = ee.Pos
+	}
+	if pos >= 0 {
+		// Line up with the expression after the "> " prompt
+		fmt.Fprintf(r.out, "  %s^\n", strings.Repeat(" ", pos))
+	}
+	fmt.Fprintln(r.out, "error:", err)
+}
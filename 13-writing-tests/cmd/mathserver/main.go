@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/dahc36/learning-go/13-writing-tests/solver"
+)
+
+// Serves the protocol used by solver.RemoteSolver, to run the integration tests against it:
+// `$ go run ./13-writing-tests/cmd/mathserver` and in another terminal
+// `$ MATH_SERVER_URL=http://localhost:8080 go test -tags integration ./13-writing-tests/solver`
+func main() {
+	addr := flag.String("addr", ":8080", "address to listen on")
+	readTimeout := flag.Duration("read-timeout", 5*time.Second, "maximum duration for reading a request")
+	writeTimeout := flag.Duration("write-timeout", 10*time.Second, "maximum duration for writing a response")
+	solveTimeout := flag.Duration("solve-timeout", 2*time.Second, "maximum duration for solving an expression")
+	shutdownTimeout := flag.Duration("shutdown-timeout", 10*time.Second, "time to let requests finish when stopping")
+	maxExpression := flag.Int("max-expression", 4096, "maximum expression length in bytes")
+	maxBatch := flag.Int("max-batch", 100, "maximum number of expressions in a batch request")
+	flag.Parse()
+
+	s := http.Server{
+		Addr:           *addr,
+		ReadTimeout:    *readTimeout,
+		WriteTimeout:   *writeTimeout,
+		IdleTimeout:    120 * time.Second,
+		MaxHeaderBytes: *maxExpression + 4096,
+		Handler: solver.MathServer{
+			Solver:             solver.LocalSolver{},
+			MaxExpressionBytes: *maxExpression,
+			SolveTimeout:       *solveTimeout,
+			MaxBatchSize:       *maxBatch,
+		},
+	}
+
+	// The ctx is canceled on Ctrl+C or when the process is asked to stop
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		fmt.Println("Listening for requests to", *addr)
+		if err := s.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+	<-ctx.Done()
+
+	// Shutdown stops accepting connections and waits for the active requests to finish
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), *shutdownTimeout)
+	defer cancel()
+	if err := s.Shutdown(shutdownCtx); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Server stopped")
+}
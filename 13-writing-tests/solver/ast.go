@@ -0,0 +1,252 @@
+package solver
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// env has the variables an expression can read and assign
+type env map[string]float64
+
+// Node is an element of the syntax tree returned by Parse: a Number, Var, Neg, Binary,
+// Call or Assign. String writes it back as an expression that parses to the same tree,
+// with only the parentheses it needs.
+type Node interface {
+	String() string
+	eval(vars env) (float64, error)
+}
+
+// Eval evaluates n, reading and assigning the variables in vars. vars can be nil, the
+// assignments are then only kept during the evaluation.
+func Eval(n Node, vars map[string]float64) (float64, error) {
+	if vars == nil {
+		vars = env{}
+	}
+	return n.eval(vars)
+}
+
+// Number keeps the text it was parsed from, so that exact arithmetic doesn't start from a
+// float. Text is empty for numbers computed by Simplify or Derive. Value is an infinity
+// when the text is too large for a float64, like 1e400.
+type Number struct {
+	Value float64
+	Text  string
+	Pos   int
+}
+
+func (n Number) String() string {
+	if n.Text != "" {
+		return n.Text
+	}
+	if n.Value == 0 {
+		// Not -0
+		return "0"
+	}
+	return strconv.FormatFloat(n.Value, 'f', -1, 64)
+}
+
+func (n Number) eval(vars env) (float64, error) {
+	if !finite(n.Value) {
+		return 0, EvalError{Pos: n.Pos, Err: fmt.Errorf("%w: %s", ErrNotFinite, n)}
+	}
+	return n.Value, nil
+}
+
+// Var is a variable or one of the constants pi and e
+type Var struct {
+	Name string
+	Pos  int
+}
+
+func (v Var) String() string {
+	return v.Name
+}
+
+func (v Var) eval(vars env) (float64, error) {
+	if c, ok := constants[v.Name]; ok {
+		return c, nil
+	}
+	if x, ok := vars[v.Name]; ok {
+		return x, nil
+	}
+	return 0, EvalError{Pos: v.Pos, Err: fmt.Errorf("%w %q", ErrUndefined, v.Name)}
+}
+
+// Neg is the unary minus
+type Neg struct {
+	X Node
+}
+
+func (n Neg) String() string {
+	if precedence(n.X) < precPower {
+		return "-(" + n.X.String() + ")"
+	}
+	return "-" + n.X.String()
+}
+
+func (n Neg) eval(vars env) (float64, error) {
+	v, err := n.X.eval(vars)
+	return -v, err
+}
+
+// Binary is one of +, -, *, / and ^
+type Binary struct {
+	Op          byte
+	Pos         int
+	Left, Right Node
+}
+
+func (b Binary) String() string {
+	left, right := b.Left.String(), b.Right.String()
+	p := precedence(b)
+	if b.Op == '^' {
+		// ^ is right associative, and its exponent can have a unary minus
+		if precedence(b.Left) <= p {
+			left = "(" + left + ")"
+		}
+		if precedence(b.Right) < precUnary {
+			right = "(" + right + ")"
+		}
+	} else {
+		if precedence(b.Left) < p {
+			left = "(" + left + ")"
+		}
+		if precedence(b.Right) <= p {
+			right = "(" + right + ")"
+		}
+	}
+	return fmt.Sprintf("%s %c %s", left, b.Op, right)
+}
+
+func (b Binary) eval(vars env) (float64, error) {
+	l, err := b.Left.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.Right.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	var v float64
+	switch b.Op {
+	case '+':
+		v = l + r
+	case '-':
+		v = l - r
+	case '*':
+		v = l * r
+	case '/':
+		if r == 0 {
+			return 0, EvalError{Pos: b.Pos, Err: ErrDivisionByZero}
+		}
+		v = l / r
+	case '^':
+		v = math.Pow(l, r)
+	default:
+		return 0, fmt.Errorf("operator %c not supported", b.Op)
+	}
+	// Overflows like 10 ^ 300 * 10 ^ 300 give an infinity
+	if !finite(v) {
+		return 0, EvalError{Pos: b.Pos, Err: fmt.Errorf("%w: %g %c %g", ErrNotFinite, l, b.Op, r)}
+	}
+	return v, nil
+}
+
+// Call is a call to one of the built-in functions
+type Call struct {
+	Name string
+	Pos  int
+	Args []Node
+}
+
+func (c Call) String() string {
+	args := make([]string, len(c.Args))
+	for i, arg := range c.Args {
+		args[i] = arg.String()
+	}
+	return c.Name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (c Call) eval(vars env) (float64, error) {
+	f, ok := functions[c.Name]
+	if !ok {
+		return 0, EvalError{Pos: c.Pos, Err: fmt.Errorf("unknown function %s", c.Name)}
+	}
+	if err := f.checkArgs(c.Name, len(c.Args)); err != nil {
+		return 0, EvalError{Pos: c.Pos, Err: err}
+	}
+	args := make([]float64, len(c.Args))
+	for i, arg := range c.Args {
+		v, err := arg.eval(vars)
+		if err != nil {
+			return 0, err
+		}
+		args[i] = v
+	}
+	v := f.call(args)
+	if !finite(v) {
+		return 0, EvalError{Pos: c.Pos, Err: fmt.Errorf("%w: %s(%s)", ErrNotFinite, c.Name, formatArgs(args))}
+	}
+	return v, nil
+}
+
+func formatArgs(args []float64) string {
+	s := make([]string, len(args))
+	for i, a := range args {
+		s[i] = strconv.FormatFloat(a, 'g', -1, 64)
+	}
+	return strings.Join(s, ", ")
+}
+
+// Assign stores the value of X in the variable Name, only if X could be evaluated
+type Assign struct {
+	Name string
+	X    Node
+}
+
+func (a Assign) String() string {
+	return a.Name + " = " + a.X.String()
+}
+
+func (a Assign) eval(vars env) (float64, error) {
+	v, err := a.X.eval(vars)
+	if err != nil {
+		return 0, err
+	}
+	vars[a.Name] = v
+	return v, nil
+}
+
+// Precedence levels used by String to decide where parentheses are needed
+const (
+	precAssign = iota
+	precSum
+	precProduct
+	precUnary
+	precPower
+	precAtom
+)
+
+func precedence(n Node) int {
+	switch n := n.(type) {
+	case Assign:
+		return precAssign
+	case Binary:
+		switch n.Op {
+		case '+', '-':
+			return precSum
+		case '*', '/':
+			return precProduct
+		}
+		return precPower
+	case Neg:
+		return precUnary
+	case Number:
+		if strings.HasPrefix(n.String(), "-") {
+			return precUnary
+		}
+	}
+	return precAtom
+}
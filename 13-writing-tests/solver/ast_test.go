@@ -0,0 +1,175 @@
+package solver
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNodeString(t *testing.T) {
+	data := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{"spaces", "2+2*10", "2 + 2 * 10"},
+		{"needed_parens", "(2 + 2) * 10", "(2 + 2) * 10"},
+		{"redundant_parens", "((2 * 3)) + (4)", "2 * 3 + 4"},
+		{"left_associative", "10 - (4 - 3)", "10 - (4 - 3)"},
+		{"left_associative_no_parens", "(10 - 4) - 3", "10 - 4 - 3"},
+		{"division", "a / (b * c)", "a / (b * c)"},
+		{"power_right_associative", "2 ^ (3 ^ 2)", "2 ^ 3 ^ 2"},
+		{"power_left_parens", "(2 ^ 3) ^ 2", "(2 ^ 3) ^ 2"},
+		{"negative_base", "(-2) ^ 2", "(-2) ^ 2"},
+		{"negative_power", "-2 ^ 2", "-2 ^ 2"},
+		{"negative_exponent", "2 ^ -x", "2 ^ -x"},
+		{"negate_sum", "-(x + 1)", "-(x + 1)"},
+		{"double_negate", "- -x", "-(-x)"},
+		{"calls", "max(1,x+1, sqrt( 2 ))", "max(1, x + 1, sqrt(2))"},
+		{"assignment", "y=x^2", "y = x ^ 2"},
+		{"decimals", ".5 + 1.", ".5 + 1."},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			n, err := Parse(d.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n.String() != d.expected {
+				t.Errorf("expected `%s`, got `%s`", d.expected, n.String())
+			}
+			// What String writes has to parse back to the same tree
+			again, err := Parse(n.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again.String() != n.String() {
+				t.Errorf("`%s` parsed back as `%s`", n.String(), again.String())
+			}
+		})
+	}
+
+	if _, err := Parse("2 +"); !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("expected ErrInvalidExpression, got %v", err)
+	}
+}
+
+func TestSimplify(t *testing.T) {
+	data := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{"identity", "x * 1 + 0", "x"},
+		{"folding", "2 * 3 + x", "6 + x"},
+		{"zero_product", "0 * (x + y) + y", "y"},
+		{"nested", "(x + 0) * (1 * y) ^ 1", "x * y"},
+		{"constant_first", "x * 3", "3 * x"},
+		{"merge_constants", "2 * (3 * x)", "6 * x"},
+		{"reciprocal", "y * (1 / x)", "y / x"},
+		{"zero_numerator", "0 / (x + 1)", "0"},
+		{"zero_denominator", "x / 0", "x / 0"},
+		{"sign_on_constant", "2 * -x", "-2 * x"},
+		{"minus_negative", "x - -y", "x + y"},
+		{"plus_negative", "x + -y", "x - y"},
+		{"zero_minus", "0 - x", "-x"},
+		{"same_terms", "(x + 1) - (x + 1)", "0"},
+		{"same_division", "sin(x) / sin(x)", "1"},
+		{"power_zero", "(x + 1) ^ 0", "1"},
+		{"whole_call", "sqrt(16) * x", "4 * x"},
+		{"keeps_log", "log(2) * x", "log(2) * x"},
+		{"log_e", "log(e) * x", "x"},
+		{"keeps_pi", "2 * pi", "2 * pi"},
+		{"keeps_division_by_zero", "x + 1 / 0", "x + 1 / 0"},
+		{"assignment", "y = x * 1", "y = x"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			n, err := Parse(d.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s := Simplify(n)
+			if s.String() != d.expected {
+				t.Errorf("expected `%s`, got `%s`", d.expected, s.String())
+			}
+			// Simplifying doesn't change the result
+			vars := map[string]float64{"x": 1.5, "y": -2}
+			before, errBefore := Eval(n, vars)
+			after, errAfter := Eval(s, vars)
+			if errBefore == nil && (errAfter != nil || before != after) {
+				t.Errorf("expected %g, got %g, %v", before, after, errAfter)
+			}
+		})
+	}
+}
+
+func TestEval(t *testing.T) {
+	data := []struct {
+		name       string
+		expression string
+		vars       map[string]float64
+		expected   float64
+		errMsg     string
+	}{
+		{"nil_vars", "2 * pi", nil, 2 * math.Pi, ""},
+		{"assign_nil_vars", "x = 3", nil, 3, ""},
+		{"undefined_nil_vars", "x + 1", nil, 0, `undefined variable "x"`},
+		{"vars", "x * y", map[string]float64{"x": 2, "y": 5}, 10, ""},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			n, err := Parse(d.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result, err := Eval(n, d.vars)
+			if result != d.expected {
+				t.Errorf("expected %g, got %g", d.expected, result)
+			}
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != d.errMsg {
+				t.Errorf("expected error `%s`, got `%s`", d.errMsg, errMsg)
+			}
+		})
+	}
+
+	// The assignment is kept in a map that was passed in
+	vars := map[string]float64{}
+	n, err := Parse("x = 4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Eval(n, vars); err != nil || vars["x"] != 4 {
+		t.Errorf("expected x to be 4, got %v, %v", vars, err)
+	}
+}
+
+func TestInvalidCalls(t *testing.T) {
+	// Only the parser checked the arguments before, a Call built by hand could panic
+	data := []struct {
+		name string
+		call Call
+	}{
+		{"no_arguments", Call{Name: "sqrt"}},
+		{"missing_argument", Call{Name: "pow", Args: []Node{Number{Value: 2}}}},
+		{"extra_argument", Call{Name: "sin", Args: []Node{Var{Name: "x"}, Var{Name: "x"}}}},
+		{"unknown", Call{Name: "foo", Args: []Node{Var{Name: "x"}}}},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			if _, err := Eval(d.call, map[string]float64{"x": 1}); err == nil {
+				t.Error("expected Eval to fail")
+			}
+			if s := Simplify(d.call); s.String() != d.call.String() {
+				t.Errorf("expected `%s` to be kept, got `%s`", d.call, s)
+			}
+			if _, err := Derive(d.call, "x"); err == nil {
+				t.Error("expected Derive to fail")
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package solver
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen matches ErrServerUnavailable as well
+var ErrCircuitOpen = fmt.Errorf("circuit breaker is open: %w", ErrServerUnavailable)
+
+type breakerState int
+
+const (
+	breakerClosed breakerState = iota
+	breakerOpen
+	breakerHalfOpen
+)
+
+// CircuitBreaker stops calls to an unhealthy server. After FailureThreshold failures in a
+// row it opens and every call fails with ErrCircuitOpen. Once OpenTimeout has passed a
+// single trial call is let through: if it succeeds the breaker closes again, otherwise it
+// stays open for another OpenTimeout. A CircuitBreaker must not be copied after first use.
+type CircuitBreaker struct {
+	// FailureThreshold is how many failures in a row open the breaker, the default is 5
+	FailureThreshold int
+	// OpenTimeout is how long the breaker stays open before a trial call, the default is 10s
+	OpenTimeout time.Duration
+	// Now is used instead of time.Now when it's set, mostly for tests
+	Now func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+}
+
+func (cb *CircuitBreaker) now() time.Time {
+	if cb.Now != nil {
+		return cb.Now()
+	}
+	return time.Now()
+}
+
+// Allow reports whether a call can be made now. Every call that was allowed must be
+// followed by a call to Record with its outcome.
+func (cb *CircuitBreaker) Allow() error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	switch cb.state {
+	case breakerOpen:
+		openTimeout := cb.OpenTimeout
+		if openTimeout <= 0 {
+			openTimeout = 10 * time.Second
+		}
+		if cb.now().Sub(cb.openedAt) < openTimeout {
+			return ErrCircuitOpen
+		}
+		cb.state = breakerHalfOpen
+		return nil
+	case breakerHalfOpen:
+		// The trial call is still in flight
+		return ErrCircuitOpen
+	}
+	return nil
+}
+
+// Record reports the outcome of a call that was allowed
+func (cb *CircuitBreaker) Record(success bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if success {
+		cb.state = breakerClosed
+		cb.failures = 0
+		return
+	}
+	cb.failures++
+	threshold := cb.FailureThreshold
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cb.state == breakerHalfOpen || cb.failures >= threshold {
+		cb.state = breakerOpen
+		cb.openedAt = cb.now()
+	}
+}
+
+// abandon is used instead of Record when a call ended without telling anything about the
+// server's health. A trial call gets retried on the next Allow.
+func (cb *CircuitBreaker) abandon() {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if cb.state == breakerHalfOpen {
+		cb.state = breakerOpen
+		cb.openedAt = time.Time{}
+	}
+}
@@ -0,0 +1,157 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNotDifferentiable is returned by Derive for min and max
+var ErrNotDifferentiable = errors.New("not differentiable")
+
+// Derive returns the simplified derivative of n with respect to variable, any other
+// variable is taken as a constant. The derivative of an assignment is the one of its
+// right side.
+func Derive(n Node, variable string) (Node, error) {
+	if _, ok := constants[variable]; ok || variable == "" {
+		return nil, fmt.Errorf("cannot derive with respect to %q", variable)
+	}
+	d, err := derive(n, variable)
+	if err != nil {
+		return nil, err
+	}
+	return Simplify(d), nil
+}
+
+func derive(n Node, x string) (Node, error) {
+	switch n := n.(type) {
+	case Number:
+		return Number{Value: 0}, nil
+	case Var:
+		if n.Name == x {
+			return Number{Value: 1}, nil
+		}
+		return Number{Value: 0}, nil
+	case Neg:
+		d, err := derive(n.X, x)
+		if err != nil {
+			return nil, err
+		}
+		return Neg{X: d}, nil
+	case Assign:
+		return derive(n.X, x)
+	case Binary:
+		dl, err := derive(n.Left, x)
+		if err != nil {
+			return nil, err
+		}
+		dr, err := derive(n.Right, x)
+		if err != nil {
+			return nil, err
+		}
+		u, v := n.Left, n.Right
+		switch n.Op {
+		case '+', '-':
+			return Binary{Op: n.Op, Left: dl, Right: dr}, nil
+		case '*':
+			return add(mul(dl, v), mul(u, dr)), nil
+		case '/':
+			return div(sub(mul(dl, v), mul(u, dr)), pow(v, Number{Value: 2})), nil
+		case '^':
+			return derivePow(u, v, dl, dr, x), nil
+		}
+		return nil, fmt.Errorf("operator %c not supported", n.Op)
+	case Call:
+		return deriveCall(n, x)
+	}
+	return nil, fmt.Errorf("%T not supported", n)
+}
+
+// derivePow uses the power rule when the exponent doesn't depend on x, and
+// d(u^v) = u^v * (v' * log(u) + v * u' / u) when it does
+func derivePow(u, v, du, dv Node, x string) Node {
+	if !dependsOn(v, x) {
+		return mul(mul(v, pow(u, sub(v, Number{Value: 1}))), du)
+	}
+	if !dependsOn(u, x) {
+		// a ^ v, the du term of the general form below is 0
+		return mul(mul(pow(u, v), call("log", u)), dv)
+	}
+	return mul(pow(u, v), add(mul(dv, call("log", u)), div(mul(v, du), u)))
+}
+
+func deriveCall(c Call, x string) (Node, error) {
+	f, ok := functions[c.Name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s", c.Name)
+	}
+	if err := f.checkArgs(c.Name, len(c.Args)); err != nil {
+		return nil, err
+	}
+	if c.Name == "pow" {
+		du, err := derive(c.Args[0], x)
+		if err != nil {
+			return nil, err
+		}
+		dv, err := derive(c.Args[1], x)
+		if err != nil {
+			return nil, err
+		}
+		return derivePow(c.Args[0], c.Args[1], du, dv, x), nil
+	}
+	if c.Name == "min" || c.Name == "max" {
+		return nil, fmt.Errorf("%s is %w", c.Name, ErrNotDifferentiable)
+	}
+	u := c.Args[0]
+	du, err := derive(u, x)
+	if err != nil {
+		return nil, err
+	}
+	// Chain rule, f(u)' = f'(u) * u'
+	switch c.Name {
+	case "sqrt":
+		return div(du, mul(Number{Value: 2}, c)), nil
+	case "sin":
+		return mul(call("cos", u), du), nil
+	case "cos":
+		return mul(Neg{X: call("sin", u)}, du), nil
+	case "tan":
+		return div(du, pow(call("cos", u), Number{Value: 2})), nil
+	case "exp":
+		return mul(c, du), nil
+	case "log":
+		return div(du, u), nil
+	case "abs":
+		return mul(div(u, c), du), nil
+	}
+	return nil, fmt.Errorf("%s is %w", c.Name, ErrNotDifferentiable)
+}
+
+// dependsOn reports whether the variable x appears in n
+func dependsOn(n Node, x string) bool {
+	switch n := n.(type) {
+	case Var:
+		return n.Name == x
+	case Neg:
+		return dependsOn(n.X, x)
+	case Binary:
+		return dependsOn(n.Left, x) || dependsOn(n.Right, x)
+	case Call:
+		for _, arg := range n.Args {
+			if dependsOn(arg, x) {
+				return true
+			}
+		}
+	case Assign:
+		return dependsOn(n.X, x)
+	}
+	return false
+}
+
+func add(l, r Node) Node { return Binary{Op: '+', Left: l, Right: r} }
+func sub(l, r Node) Node { return Binary{Op: '-', Left: l, Right: r} }
+func mul(l, r Node) Node { return Binary{Op: '*', Left: l, Right: r} }
+func div(l, r Node) Node { return Binary{Op: '/', Left: l, Right: r} }
+func pow(l, r Node) Node { return Binary{Op: '^', Left: l, Right: r} }
+func call(name string, args ...Node) Node {
+	return Call{Name: name, Args: args}
+}
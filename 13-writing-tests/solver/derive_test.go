@@ -0,0 +1,97 @@
+package solver
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDerive(t *testing.T) {
+	data := []struct {
+		name       string
+		expression string
+		variable   string
+		expected   string
+	}{
+		{"constant", "42", "x", "0"},
+		{"linear", "3 * x + 2", "x", "3"},
+		{"polynomial", "3 * x ^ 2 + 2 * x + 1", "x", "6 * x + 2"},
+		{"negative_power", "1 / x", "x", "-1 / x ^ 2"},
+		{"other_variable", "y * x ^ 2", "x", "y * (2 * x)"},
+		{"with_respect_to_y", "y * x ^ 2", "y", "x ^ 2"},
+		{"product", "x * sin(x)", "x", "sin(x) + x * cos(x)"},
+		{"quotient", "x / (x + 1)", "x", "(x + 1 - x) / (x + 1) ^ 2"},
+		{"chain_sin", "sin(2 * x)", "x", "2 * cos(2 * x)"},
+		{"chain_cos", "cos(2 * x)", "x", "-2 * sin(2 * x)"},
+		{"tan", "tan(x)", "x", "1 / cos(x) ^ 2"},
+		{"exp", "exp(3 * x)", "x", "3 * exp(3 * x)"},
+		{"log", "log(x)", "x", "1 / x"},
+		{"sqrt", "sqrt(x)", "x", "1 / (2 * sqrt(x))"},
+		{"abs", "abs(x)", "x", "x / abs(x)"},
+		{"pow", "pow(x, 3)", "x", "3 * x ^ 2"},
+		{"variable_exponent", "2 ^ x", "x", "2 ^ x * log(2)"},
+		{"variable_exponent_var", "a ^ x", "x", "a ^ x * log(a)"},
+		{"variable_exponent_e", "e ^ x", "x", "e ^ x"},
+		{"variable_exponent_e_chain", "e ^ (2 * x)", "x", "2 * e ^ (2 * x)"},
+		{"variable_exponent_chain", "2 ^ (3 * x)", "x", "3 * (2 ^ (3 * x) * log(2))"},
+		{"negate", "-x ^ 3", "x", "-3 * x ^ 2"},
+		{"assignment", "y = x ^ 2", "x", "2 * x"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			n, err := Parse(d.expression)
+			if err != nil {
+				t.Fatal(err)
+			}
+			dn, err := Derive(n, d.variable)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if dn.String() != d.expected {
+				t.Errorf("expected `%s`, got `%s`", d.expected, dn.String())
+			}
+		})
+	}
+}
+
+// The derivatives have to match the slope measured around a few points
+func TestDeriveNumerically(t *testing.T) {
+	expressions := []string{
+		"x ^ 3 - 2 * x", "sin(x) * cos(x)", "exp(-x ^ 2)", "log(x ^ 2 + 1)",
+		"sqrt(x + 4) / (x + 5)", "x ^ x", "pow(2, x) * tan(x / 4)", "abs(x - 0.1)",
+	}
+	const h = 1e-6
+	for _, expression := range expressions {
+		n, err := Parse(expression)
+		if err != nil {
+			t.Fatal(err)
+		}
+		dn, err := Derive(n, "x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, x := range []float64{0.5, 1, 2.5} {
+			above, _ := Eval(n, map[string]float64{"x": x + h})
+			below, _ := Eval(n, map[string]float64{"x": x - h})
+			slope := (above - below) / (2 * h)
+			got, err := Eval(dn, map[string]float64{"x": x})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(got-slope) > 1e-5*math.Max(1, math.Abs(slope)) {
+				t.Errorf("d/dx %s at %g: expected %g, got %g from %s", expression, x, slope, got, dn)
+			}
+		}
+	}
+}
+
+func TestDeriveErrors(t *testing.T) {
+	n, _ := Parse("max(x, 1)")
+	if _, err := Derive(n, "x"); !errors.Is(err, ErrNotDifferentiable) {
+		t.Errorf("expected ErrNotDifferentiable, got %v", err)
+	}
+	n, _ = Parse("x")
+	if _, err := Derive(n, "pi"); err == nil {
+		t.Error("expected an error deriving with respect to pi")
+	}
+}
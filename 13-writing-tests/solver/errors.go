@@ -0,0 +1,58 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrInvalidExpression = errors.New("invalid expression")
+	ErrDivisionByZero    = errors.New("division by zero")
+	// ErrServerUnavailable is matched by 5xx answers, network errors and an open circuit breaker
+	ErrServerUnavailable = errors.New("math server unavailable")
+)
+
+// ExpressionError is returned for an expression that can't be parsed. It matches
+// ErrInvalidExpression with errors.Is, and Err has the details when they are known.
+type ExpressionError struct {
+	Expression string
+	Err        error
+}
+
+func (ee ExpressionError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalidExpression, ee.Expression)
+}
+
+func (ee ExpressionError) Unwrap() []error {
+	if ee.Err == nil {
+		return []error{ErrInvalidExpression}
+	}
+	return []error{ErrInvalidExpression, ee.Err}
+}
+
+// StatusError is an answer from the math server that isn't a 200 and can't be mapped to
+// one of the other errors. Code is only set when the JSON protocol is used, it lets
+// errors.Is match ErrUndefined and ErrNotFinite as well.
+type StatusError struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (se StatusError) Error() string {
+	return se.Message
+}
+
+func (se StatusError) Unwrap() error {
+	if se.StatusCode >= http.StatusInternalServerError {
+		return ErrServerUnavailable
+	}
+	switch se.Code {
+	case CodeUndefined:
+		return ErrUndefined
+	case CodeNotFinite:
+		return ErrNotFinite
+	}
+	return nil
+}
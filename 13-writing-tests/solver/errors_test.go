@@ -0,0 +1,123 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// The same errors come out of a LocalSolver, and out of a RemoteSolver with either protocol
+func TestSolverErrors(t *testing.T) {
+	server := httptest.NewServer(MathServer{Solver: LocalSolver{}})
+	defer server.Close()
+	solvers := []struct {
+		name   string
+		solver MathSolver
+	}{
+		{"local", LocalSolver{}},
+		{"remote_text", RemoteSolver{MathServerURL: server.URL, Client: server.Client()}},
+		{"remote_json", RemoteSolver{MathServerURL: server.URL, Client: server.Client(), JSON: true}},
+	}
+	data := []struct {
+		name       string
+		expression string
+		expected   error
+		errMsg     string
+	}{
+		{"invalid", "( 2 + 2 * 10", ErrInvalidExpression, "invalid expression: ( 2 + 2 * 10"},
+		{"division_by_zero", "1 / (2 - 2)", ErrDivisionByZero, "division by zero"},
+	}
+	for _, s := range solvers {
+		for _, d := range data {
+			t.Run(s.name+"_"+d.name, func(t *testing.T) {
+				_, err := s.solver.Resolve(context.Background(), d.expression)
+				if !errors.Is(err, d.expected) {
+					t.Fatalf("expected %v, got %v", d.expected, err)
+				}
+				if err.Error() != d.errMsg {
+					t.Errorf("expected error `%s`, got `%s`", d.errMsg, err.Error())
+				}
+				var ee ExpressionError
+				isExpressionErr := errors.As(err, &ee)
+				if isExpressionErr != (d.expected == ErrInvalidExpression) {
+					t.Errorf("unexpected ExpressionError %v", err)
+				}
+				if isExpressionErr && ee.Expression != d.expression {
+					t.Errorf("expected expression `%s`, got `%s`", d.expression, ee.Expression)
+				}
+			})
+		}
+	}
+}
+
+func TestRemoteSolverUnavailable(t *testing.T) {
+	data := []struct {
+		name    string
+		handler http.HandlerFunc
+		json    bool
+	}{
+		{"text_503", func(rw http.ResponseWriter, r *http.Request) {
+			http.Error(rw, "overloaded", http.StatusServiceUnavailable)
+		}, false},
+		{"json_503", func(rw http.ResponseWriter, r *http.Request) {
+			writeJSONError(rw, http.StatusServiceUnavailable, CodeServerUnavailable, "overloaded")
+		}, true},
+		{"json_proxy_502", func(rw http.ResponseWriter, r *http.Request) {
+			http.Error(rw, "bad gateway", http.StatusBadGateway)
+		}, true},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			server := httptest.NewServer(d.handler)
+			defer server.Close()
+			rs := RemoteSolver{MathServerURL: server.URL, Client: server.Client(), JSON: d.json}
+			_, err := rs.Resolve(context.Background(), "1")
+			if !errors.Is(err, ErrServerUnavailable) {
+				t.Errorf("expected ErrServerUnavailable, got %v", err)
+			}
+			var se StatusError
+			if !errors.As(err, &se) || se.StatusCode < 500 {
+				t.Errorf("expected a 5xx StatusError, got %v", err)
+			}
+		})
+	}
+
+	// Nothing listens on a closed server
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	_, err := RemoteSolver{MathServerURL: server.URL, Client: http.DefaultClient}.Resolve(context.Background(), "1")
+	if !errors.Is(err, ErrServerUnavailable) {
+		t.Errorf("expected ErrServerUnavailable, got %v", err)
+	}
+}
+
+func TestRemoteSolverResolveBatch(t *testing.T) {
+	server := httptest.NewServer(MathServer{Solver: LocalSolver{}, MaxBatchSize: 3})
+	defer server.Close()
+	rs := RemoteSolver{MathServerURL: server.URL + "/", Client: server.Client()}
+
+	results, err := rs.ResolveBatch(context.Background(), []string{"2 + 2 * 10", "( 2", "1 / 0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("expected 3 results, got %d", len(results))
+	}
+	if results[0].Value != 22 || results[0].Err != nil || results[0].Line != 1 {
+		t.Errorf("unexpected first result %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, ErrInvalidExpression) || results[1].Expression != "( 2" {
+		t.Errorf("expected ErrInvalidExpression, got %+v", results[1])
+	}
+	if !errors.Is(results[2].Err, ErrDivisionByZero) {
+		t.Errorf("expected ErrDivisionByZero, got %+v", results[2])
+	}
+
+	_, err = rs.ResolveBatch(context.Background(), []string{"1", "2", "3", "4"})
+	var se StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusRequestEntityTooLarge || se.Code != CodeInvalidRequest {
+		t.Errorf("expected a 413 StatusError, got %v", err)
+	}
+}
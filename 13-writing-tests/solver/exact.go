@@ -0,0 +1,323 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrNotExact is returned by an ExactSolver for what can't be computed exactly with
+// rationals, like pi, sqrt(2) or 2 ^ 0.5
+var ErrNotExact = errors.New("not exact")
+
+// maxExactBits limits the size of the numerator and denominator that ^ and pow can create
+// in exact mode. The result of 10 ^ 10000000 would take megabytes and a long time to
+// compute, and so would (2 ^ 10000) ^ 10000, even though each exponent is small.
+const maxExactBits = 1 << 20
+
+// RoundingMode is how an ExactSolver rounds a result to its Scale
+type RoundingMode int
+
+const (
+	// RoundExact doesn't round: results with a finite decimal expansion are written in
+	// full, like 0.3, and any other as a fraction, like 1/3
+	RoundExact RoundingMode = iota
+	// RoundHalfUp rounds to the nearest, ties away from zero: 0.125 -> 0.13, -0.125 -> -0.13
+	RoundHalfUp
+	// RoundHalfEven rounds to the nearest, ties to the even digit: 0.125 -> 0.12, 0.135 -> 0.14
+	RoundHalfEven
+	// RoundDown rounds toward zero
+	RoundDown
+	// RoundUp rounds away from zero
+	RoundUp
+	// RoundFloor rounds toward negative infinity
+	RoundFloor
+	// RoundCeiling rounds toward positive infinity
+	RoundCeiling
+)
+
+// ExactSolver evaluates like a LocalSolver, but with math/big.Rat instead of float64, so
+// 0.1 + 0.2 is exactly 0.3. Only +, -, *, /, ^ with integer exponents, abs, min, max and
+// pow with an integer exponent are supported, anything else fails with ErrNotExact.
+// big.Rat is in the standard library, unlike the shopspring/decimal used in chapter 9,
+// and a division like 1 / 3 stays exact until the result is rounded.
+//
+// Results are rounded to Scale digits after the decimal point using Mode, and always
+// written with Scale digits, like 0.30 for a Scale of 2. The zero value doesn't round,
+// see RoundExact.
+type ExactSolver struct {
+	Scale int
+	Mode  RoundingMode
+}
+
+// ResolveExact returns the result as a decimal string, or a fraction with RoundExact
+func (es ExactSolver) ResolveExact(ctx context.Context, expression string) (string, error) {
+	r, err := es.resolve(ctx, expression)
+	if err != nil {
+		return "", err
+	}
+	if es.Mode == RoundExact {
+		return formatExact(r), nil
+	}
+	return formatScaled(roundRat(r, es.Scale, es.Mode), es.Scale), nil
+}
+
+// Resolve returns the rounded result as a float64, so an ExactSolver can be used as a
+// MathSolver. The float64 is the closest one to the exact result, and like for a
+// LocalSolver a result too large for a float64, like 10 ^ 400, fails with ErrNotFinite.
+func (es ExactSolver) Resolve(ctx context.Context, expression string) (float64, error) {
+	r, err := es.resolve(ctx, expression)
+	if err != nil {
+		return 0, err
+	}
+	if es.Mode != RoundExact {
+		scaled := roundRat(r, es.Scale, es.Mode)
+		r = new(big.Rat).SetFrac(scaled, pow10(es.Scale))
+	}
+	f, _ := r.Float64()
+	if !finite(f) {
+		// The whole expression is the problem, not one operation in it
+		return 0, EvalError{Pos: 0, Err: fmt.Errorf("%w: %s is too large for a float64", ErrNotFinite, expression)}
+	}
+	return f, nil
+}
+
+func (es ExactSolver) resolve(ctx context.Context, expression string) (*big.Rat, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if es.Scale < 0 {
+		return nil, fmt.Errorf("scale must not be negative, got %d", es.Scale)
+	}
+	n, err := parse(expression)
+	if err != nil {
+		return nil, ExpressionError{Expression: expression, Err: err}
+	}
+	return evalExact(n, map[string]*big.Rat{})
+}
+
+// evalExact is the exact version of Node.eval
+func evalExact(n Node, vars map[string]*big.Rat) (*big.Rat, error) {
+	switch n := n.(type) {
+	case Number:
+		if n.Text == "" {
+			// Computed by Simplify or Derive, the float64 is all there is
+			return new(big.Rat).SetFloat64(n.Value), nil
+		}
+		r, err := parseRat(n.Text)
+		if err != nil {
+			return nil, EvalError{Pos: n.Pos, Err: err}
+		}
+		return r, nil
+	case Var:
+		if _, ok := constants[n.Name]; ok {
+			return nil, EvalError{Pos: n.Pos, Err: fmt.Errorf("%s is %w", n.Name, ErrNotExact)}
+		}
+		if r, ok := vars[n.Name]; ok {
+			return r, nil
+		}
+		return nil, EvalError{Pos: n.Pos, Err: fmt.Errorf("%w %q", ErrUndefined, n.Name)}
+	case Neg:
+		r, err := evalExact(n.X, vars)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Rat).Neg(r), nil
+	case Binary:
+		l, err := evalExact(n.Left, vars)
+		if err != nil {
+			return nil, err
+		}
+		r, err := evalExact(n.Right, vars)
+		if err != nil {
+			return nil, err
+		}
+		switch n.Op {
+		case '+':
+			return new(big.Rat).Add(l, r), nil
+		case '-':
+			return new(big.Rat).Sub(l, r), nil
+		case '*':
+			return new(big.Rat).Mul(l, r), nil
+		case '/':
+			if r.Sign() == 0 {
+				return nil, EvalError{Pos: n.Pos, Err: ErrDivisionByZero}
+			}
+			return new(big.Rat).Quo(l, r), nil
+		case '^':
+			v, err := powRat(l, r)
+			if err != nil {
+				return nil, EvalError{Pos: n.Pos, Err: err}
+			}
+			return v, nil
+		}
+		return nil, fmt.Errorf("operator %c not supported", n.Op)
+	case Call:
+		f, ok := functions[n.Name]
+		if !ok {
+			return nil, EvalError{Pos: n.Pos, Err: fmt.Errorf("unknown function %s", n.Name)}
+		}
+		if err := f.checkArgs(n.Name, len(n.Args)); err != nil {
+			return nil, EvalError{Pos: n.Pos, Err: err}
+		}
+		args := make([]*big.Rat, len(n.Args))
+		for i, arg := range n.Args {
+			v, err := evalExact(arg, vars)
+			if err != nil {
+				return nil, err
+			}
+			args[i] = v
+		}
+		v, err := callExact(n.Name, args)
+		if err != nil {
+			return nil, EvalError{Pos: n.Pos, Err: err}
+		}
+		return v, nil
+	case Assign:
+		v, err := evalExact(n.X, vars)
+		if err != nil {
+			return nil, err
+		}
+		vars[n.Name] = v
+		return v, nil
+	}
+	return nil, fmt.Errorf("%T not supported", n)
+}
+
+// parseRat parses the text of a number. big.Rat computes 10 ^ exponent in full, so a
+// literal like 1e100000000 is checked against maxExactBits first.
+func parseRat(text string) (*big.Rat, error) {
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		// Each unit of a decimal exponent takes a bit over 3 bits
+		exp, err := strconv.Atoi(text[i+1:])
+		if err != nil || exp > maxExactBits/4 || exp < -maxExactBits/4 {
+			return nil, fmt.Errorf("%s would be larger than %d bits, %w", text, maxExactBits, ErrNotExact)
+		}
+	}
+	r, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", text)
+	}
+	return r, nil
+}
+
+func callExact(name string, args []*big.Rat) (*big.Rat, error) {
+	switch name {
+	case "abs":
+		return new(big.Rat).Abs(args[0]), nil
+	case "pow":
+		return powRat(args[0], args[1])
+	case "min", "max":
+		m := args[0]
+		for _, a := range args[1:] {
+			if c := a.Cmp(m); (name == "min" && c < 0) || (name == "max" && c > 0) {
+				m = a
+			}
+		}
+		return m, nil
+	}
+	return nil, fmt.Errorf("%s is %w", name, ErrNotExact)
+}
+
+func powRat(base, exponent *big.Rat) (*big.Rat, error) {
+	if !exponent.IsInt() {
+		return nil, fmt.Errorf("non integer exponent %s is %w", exponent.RatString(), ErrNotExact)
+	}
+	e := exponent.Num()
+	if e.Sign() < 0 && base.Sign() == 0 {
+		return nil, ErrDivisionByZero
+	}
+	abs := new(big.Int).Abs(e)
+	// The result has up to |e| times the bits of the base, except for 0, 1 and -1
+	if bits := max(base.Num().BitLen(), base.Denom().BitLen()); bits > 1 &&
+		(!abs.IsInt64() || abs.Int64() > maxExactBits/int64(bits)) {
+		return nil, fmt.Errorf("result of %s ^ %s would be larger than %d bits, %w",
+			base.RatString(), e, maxExactBits, ErrNotExact)
+	}
+	num := new(big.Int).Exp(base.Num(), abs, nil)
+	denom := new(big.Int).Exp(base.Denom(), abs, nil)
+	if e.Sign() < 0 {
+		num, denom = denom, num
+	}
+	return new(big.Rat).SetFrac(num, denom), nil
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// roundRat returns r * 10^scale rounded to an integer with mode
+func roundRat(r *big.Rat, scale int, mode RoundingMode) *big.Int {
+	num := new(big.Int).Mul(r.Num(), pow10(scale))
+	denom := r.Denom()
+	// QuoRem truncates toward zero, rem has the sign of num
+	q, rem := new(big.Int).QuoRem(num, denom, new(big.Int))
+	if rem.Sign() == 0 {
+		return q
+	}
+	// cmpHalf is the sign of |rem| - denom/2
+	cmpHalf := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2)).Cmp(denom)
+	awayFromZero := false
+	switch mode {
+	case RoundHalfUp:
+		awayFromZero = cmpHalf >= 0
+	case RoundHalfEven:
+		awayFromZero = cmpHalf > 0 || (cmpHalf == 0 && q.Bit(0) == 1)
+	case RoundUp:
+		awayFromZero = true
+	case RoundFloor:
+		awayFromZero = num.Sign() < 0
+	case RoundCeiling:
+		awayFromZero = num.Sign() > 0
+	}
+	if awayFromZero {
+		q.Add(q, big.NewInt(int64(num.Sign())))
+	}
+	return q
+}
+
+// formatScaled writes scaled / 10^scale with exactly scale digits after the point
+func formatScaled(scaled *big.Int, scale int) string {
+	digits := new(big.Int).Abs(scaled).String()
+	if scale > 0 {
+		if len(digits) <= scale {
+			digits = strings.Repeat("0", scale-len(digits)+1) + digits
+		}
+		digits = digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+	}
+	if scaled.Sign() < 0 {
+		return "-" + digits
+	}
+	return digits
+}
+
+// formatExact writes r as a decimal when it has a finite expansion, which is when its
+// denominator only has 2 and 5 as prime factors, and as a fraction otherwise
+func formatExact(r *big.Rat) string {
+	if r.IsInt() {
+		return r.Num().String()
+	}
+	denom := new(big.Int).Set(r.Denom())
+	twos, fives := 0, 0
+	for denom.Bit(0) == 0 {
+		denom.Rsh(denom, 1)
+		twos++
+	}
+	five, rem := big.NewInt(5), new(big.Int)
+	for {
+		q, m := new(big.Int).QuoRem(denom, five, rem)
+		if m.Sign() != 0 {
+			break
+		}
+		denom = q
+		fives++
+	}
+	if denom.Cmp(big.NewInt(1)) != 0 {
+		return r.RatString()
+	}
+	scale := max(twos, fives)
+	return formatScaled(roundRat(r, scale, RoundDown), scale)
+}
@@ -0,0 +1,101 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExactSolver_ResolveExact(t *testing.T) {
+	data := []struct {
+		name       string
+		solver     ExactSolver
+		expression string
+		result     string
+		err        error
+	}{
+		{"no_drift", ExactSolver{}, "0.1 + 0.2", "0.3", nil},
+		{"exponent", ExactSolver{}, "1.5e-3 + 1e2", "100.0015", nil},
+		{"integer", ExactSolver{}, "2 ^ 70", "1180591620717411303424", nil},
+		{"beyond_float64", ExactSolver{}, "1e400 / 1e398", "100", nil},
+		{"fraction", ExactSolver{}, "1 / 3", "1/3", nil},
+		{"negative_fraction", ExactSolver{}, "-2 / 6", "-1/3", nil},
+		{"finite_decimal", ExactSolver{}, "1 / 8 - 1", "-0.875", nil},
+		{"negative_exponent", ExactSolver{}, "2 ^ -3", "0.125", nil},
+		{"functions", ExactSolver{}, "max(0.1, abs(-0.3), pow(0.5, 2))", "0.3", nil},
+		{"assignment", ExactSolver{}, "x = 0.1 * 3", "0.3", nil},
+		{"cents", ExactSolver{Scale: 2, Mode: RoundHalfEven}, "0.1 + 0.2", "0.30", nil},
+		{"scale_zero", ExactSolver{Scale: 0, Mode: RoundHalfUp}, "5 / 2", "3", nil},
+		{"small", ExactSolver{Scale: 3, Mode: RoundHalfUp}, "1 / 400", "0.003", nil},
+		{"negative_zero", ExactSolver{Scale: 2, Mode: RoundHalfUp}, "-0.001", "0.00", nil},
+		{"half_up", ExactSolver{Scale: 2, Mode: RoundHalfUp}, "0.125", "0.13", nil},
+		{"half_up_negative", ExactSolver{Scale: 2, Mode: RoundHalfUp}, "-0.125", "-0.13", nil},
+		{"half_even_down", ExactSolver{Scale: 2, Mode: RoundHalfEven}, "0.125", "0.12", nil},
+		{"half_even_up", ExactSolver{Scale: 2, Mode: RoundHalfEven}, "0.135", "0.14", nil},
+		{"half_even_above", ExactSolver{Scale: 2, Mode: RoundHalfEven}, "0.1251", "0.13", nil},
+		{"down", ExactSolver{Scale: 2, Mode: RoundDown}, "-2 / 3", "-0.66", nil},
+		{"up", ExactSolver{Scale: 2, Mode: RoundUp}, "-2 / 3", "-0.67", nil},
+		{"floor", ExactSolver{Scale: 2, Mode: RoundFloor}, "1 / 3 - 1", "-0.67", nil},
+		{"ceiling", ExactSolver{Scale: 2, Mode: RoundCeiling}, "1 / 3", "0.34", nil},
+		{"interest", ExactSolver{Scale: 2, Mode: RoundHalfEven}, "1000 * (1 + 0.05 / 12) ^ 12", "1051.16", nil},
+		{"pi", ExactSolver{}, "2 * pi", "", ErrNotExact},
+		{"sqrt", ExactSolver{}, "sqrt(4)", "", ErrNotExact},
+		{"fractional_exponent", ExactSolver{}, "4 ^ 0.5", "", ErrNotExact},
+		{"huge_exponent", ExactSolver{}, "10 ^ 1000000", "", ErrNotExact},
+		{"huge_nested_exponent", ExactSolver{}, "((2 ^ 10000) ^ 10000) ^ 10000", "", ErrNotExact},
+		{"huge_fraction", ExactSolver{}, "0.5 ^ -1000000", "", ErrNotExact},
+		{"huge_literal", ExactSolver{}, "1e100000000", "", ErrNotExact},
+		{"one_huge_exponent", ExactSolver{}, "(-1) ^ 100000000000000000001", "-1", nil},
+		{"division_by_zero", ExactSolver{}, "1 / (0.1 + 0.2 - 0.3)", "", ErrDivisionByZero},
+		{"zero_negative_power", ExactSolver{}, "0 ^ -1", "", ErrDivisionByZero},
+		{"invalid", ExactSolver{}, "1 +", "", ErrInvalidExpression},
+		{"undefined", ExactSolver{}, "x", "", ErrUndefined},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			result, err := d.solver.ResolveExact(context.Background(), d.expression)
+			if result != d.result {
+				t.Errorf("expected `%s`, got `%s`", d.result, result)
+			}
+			if !errors.Is(err, d.err) {
+				t.Errorf("expected error `%v`, got `%v`", d.err, err)
+			}
+		})
+	}
+}
+
+func TestExactSolver_Resolve(t *testing.T) {
+	// The float64 result of the exact sum is the closest float64 to 0.3, a float64 sum isn't
+	floatSum, _ := LocalSolver{}.Resolve(context.Background(), "0.1 + 0.2")
+	exactSum, err := ExactSolver{}.Resolve(context.Background(), "0.1 + 0.2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exactSum != 0.3 || floatSum == 0.3 {
+		t.Errorf("expected 0.3 and not %g, got %g", floatSum, exactSum)
+	}
+
+	p := Processor{ExactSolver{Scale: 2, Mode: RoundHalfUp}}
+	results, err := p.ProcessAll(context.Background(), strings.NewReader("19.99 * 3\n10 / 3\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if results[0].Value != 59.97 || results[1].Value != 3.33 {
+		t.Errorf("expected 59.97 and 3.33, got %g and %g", results[0].Value, results[1].Value)
+	}
+
+	// Exact, but too large for the float64 of a MathSolver, like with a LocalSolver
+	for _, expression := range []string{"10 ^ 400", "1e400"} {
+		if _, err := (ExactSolver{}).Resolve(context.Background(), expression); !errors.Is(err, ErrNotFinite) {
+			t.Errorf("expected ErrNotFinite for %s, got %v", expression, err)
+		}
+	}
+	if v, err := (ExactSolver{}).Resolve(context.Background(), "1e400 / 1e399"); err != nil || v != 10 {
+		t.Errorf("expected 10, got %g, %v", v, err)
+	}
+
+	if _, err := (ExactSolver{Scale: -1, Mode: RoundDown}).ResolveExact(context.Background(), "1"); err == nil {
+		t.Error("expected an error for a negative scale")
+	}
+}
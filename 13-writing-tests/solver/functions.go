@@ -0,0 +1,81 @@
+package solver
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+var (
+	// ErrUndefined is returned for a variable that wasn't assigned before it was used
+	ErrUndefined = errors.New("undefined variable")
+	// ErrNotFinite is returned when an operation or a function gives NaN or an infinity,
+	// like sqrt(-1) or 1e300 * 1e300
+	ErrNotFinite = errors.New("result is not a finite number")
+)
+
+// EvalError is a problem found while evaluating an expression that parsed fine, Pos is
+// the byte offset of the part of the expression that failed. The message is the one of
+// Err, so a division by zero still reads "division by zero".
+type EvalError struct {
+	Pos int
+	Err error
+}
+
+func (ee EvalError) Error() string {
+	return ee.Err.Error()
+}
+
+func (ee EvalError) Unwrap() error {
+	return ee.Err
+}
+
+var constants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// function is a built-in function, maxArgs is -1 when it takes any number of arguments
+type function struct {
+	minArgs, maxArgs int
+	call             func(args []float64) float64
+}
+
+var functions = map[string]function{
+	"sqrt": {1, 1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"sin":  {1, 1, func(a []float64) float64 { return math.Sin(a[0]) }},
+	"cos":  {1, 1, func(a []float64) float64 { return math.Cos(a[0]) }},
+	"tan":  {1, 1, func(a []float64) float64 { return math.Tan(a[0]) }},
+	"exp":  {1, 1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	// log is the natural logarithm
+	"log": {1, 1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"abs": {1, 1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"pow": {2, 2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"min": {1, -1, func(a []float64) float64 {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Min(m, v)
+		}
+		return m
+	}},
+	"max": {1, -1, func(a []float64) float64 {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Max(m, v)
+		}
+		return m
+	}},
+}
+
+// checkArgs returns an error when f can't be called with n arguments. The parser checks
+// this already, but a Call can also be built by hand.
+func (f function) checkArgs(name string, n int) error {
+	if n < f.minArgs || (f.maxArgs >= 0 && n > f.maxArgs) {
+		return fmt.Errorf("wrong number of arguments for %s, got %d", name, n)
+	}
+	return nil
+}
+
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
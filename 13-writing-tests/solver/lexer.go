@@ -0,0 +1,114 @@
+package solver
+
+import (
+	"errors"
+	"strconv"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenNumber
+	tokenIdent
+	tokenOperator
+	tokenAssign
+	tokenComma
+	tokenLParen
+	tokenRParen
+)
+
+type token struct {
+	kind  tokenKind
+	text  string
+	value float64
+	pos   int
+}
+
+// SyntaxError is a problem in the text of an expression, Pos is the byte offset where it was found
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (se SyntaxError) Error() string {
+	return se.Msg + " at " + strconv.Itoa(se.Pos)
+}
+
+// tokenize splits an expression into numbers, names, operators and punctuation, ignoring
+// spaces. The last token is always tokenEOF.
+func tokenize(expression string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(expression); {
+		c := expression[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case c == '+' || c == '-' || c == '*' || c == '/' || c == '^':
+			tokens = append(tokens, token{kind: tokenOperator, text: string(c), pos: i})
+			i++
+		case c == '=':
+			tokens = append(tokens, token{kind: tokenAssign, text: "=", pos: i})
+			i++
+		case c == ',':
+			tokens = append(tokens, token{kind: tokenComma, text: ",", pos: i})
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i})
+			i++
+		case isDigit(c) || c == '.':
+			start := i
+			for i < len(expression) && (isDigit(expression[i]) || expression[i] == '.') {
+				i++
+			}
+			i += exponentLength(expression[i:])
+			text := expression[start:i]
+			v, err := strconv.ParseFloat(text, 64)
+			// Out of range is fine here, 1e400 is a valid number that only a float64 can't
+			// hold: v is an infinity and evaluating it reports ErrNotFinite
+			if err != nil && !errors.Is(err, strconv.ErrRange) {
+				return nil, SyntaxError{Pos: start, Msg: strconv.Quote(text) + " is not a number"}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: text, value: v, pos: start})
+		case isLetter(c):
+			start := i
+			for i < len(expression) && (isLetter(expression[i]) || isDigit(expression[i])) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: expression[start:i], pos: start})
+		default:
+			return nil, SyntaxError{Pos: i, Msg: "unexpected character " + strconv.QuoteRune(rune(c))}
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(expression)}), nil
+}
+
+// exponentLength is the length of the exponent at the start of s, like e5 or E-3, or 0 if
+// there's none. An e that isn't followed by digits is left alone, so 2e is 2 and then e.
+func exponentLength(s string) int {
+	if len(s) < 2 || (s[0] != 'e' && s[0] != 'E') {
+		return 0
+	}
+	i := 1
+	if s[i] == '+' || s[i] == '-' {
+		i++
+	}
+	if i == len(s) || !isDigit(s[i]) {
+		return 0
+	}
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_'
+}
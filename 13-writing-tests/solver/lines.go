@@ -0,0 +1,118 @@
+package solver
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// DefaultMaxLineLength is used when NewLineReader gets a max length of 0 or less
+const DefaultMaxLineLength = 64 * 1024
+
+var ErrLineTooLong = errors.New("line too long")
+
+// LineError reports a problem reading a specific line, Line starts at 1
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (le LineError) Error() string {
+	return fmt.Sprintf("line %d: %v", le.Line, le.Err)
+}
+
+func (le LineError) Unwrap() error {
+	return le.Err
+}
+
+// LineReader reads expressions line by line. It accepts both \n and \r\n line endings,
+// and skips blank lines and lines starting with #.
+type LineReader struct {
+	r             *bufio.Reader
+	maxLineLength int
+	line          int
+}
+
+func NewLineReader(r io.Reader, maxLineLength int) *LineReader {
+	if maxLineLength <= 0 {
+		maxLineLength = DefaultMaxLineLength
+	}
+	return &LineReader{
+		r:             bufio.NewReader(r),
+		maxLineLength: maxLineLength,
+	}
+}
+
+// Next returns the next expression and its line number, or io.EOF when there are no more.
+// A line over the max length returns a LineError wrapping ErrLineTooLong, and reading can
+// continue with the following line. Read errors are returned in a LineError as well.
+func (lr *LineReader) Next() (string, int, error) {
+	for {
+		raw, err := lr.readLine()
+		if err == io.EOF && raw == nil {
+			return "", lr.line, io.EOF
+		}
+		lr.line++
+		if err != nil && err != io.EOF {
+			return "", lr.line, LineError{Line: lr.line, Err: err}
+		}
+		expression := bytes.TrimSpace(raw)
+		if len(expression) == 0 || expression[0] == '#' {
+			continue
+		}
+		return string(expression), lr.line, nil
+	}
+}
+
+// Read reads the input that hasn't been returned by Next yet, including what's buffered.
+// It also lets a *LineReader be passed wherever an io.Reader is expected.
+func (lr *LineReader) Read(p []byte) (int, error) {
+	return lr.r.Read(p)
+}
+
+// readLine returns the line without its line ending. It returns io.EOF along with a
+// nil line only when there's nothing left to read.
+func (lr *LineReader) readLine() ([]byte, error) {
+	var line []byte
+	tooLong := false
+	for {
+		chunk, err := lr.r.ReadSlice('\n')
+		// ReadSlice returns a slice of its buffer, so we copy it before reading again
+		if !tooLong {
+			line = append(line, chunk...)
+			if len(bytes.TrimRight(line, "\r\n")) > lr.maxLineLength {
+				// Keep reading to discard the rest of the line
+				tooLong = true
+				line = line[:0]
+			}
+		}
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && len(line) == 0 && !tooLong && len(chunk) == 0:
+			return nil, io.EOF
+		case err != nil && err != io.EOF:
+			return nil, err
+		}
+		if tooLong {
+			return []byte{}, ErrLineTooLong
+		}
+		return bytes.TrimRight(line, "\r\n"), nil
+	}
+}
+
+// oneByteReader never asks the wrapped reader for more than one byte at a time, so a
+// LineReader built on top of it doesn't read past the end of the line it returns.
+// That lets ProcessExpression be called several times with the same io.Reader.
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (obr oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return obr.r.Read(p[:1])
+}
@@ -0,0 +1,154 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLineReader(t *testing.T) {
+	data := []struct {
+		name     string
+		in       string
+		maxLen   int
+		expected []string
+		errLines []int
+	}{
+		{"lf", "1 + 1\n2 + 2\n", 0, []string{"1:1 + 1", "2:2 + 2"}, nil},
+		{"crlf", "1 + 1\r\n2 + 2\r\n", 0, []string{"1:1 + 1", "2:2 + 2"}, nil},
+		{"no final newline", "1 + 1\n2 + 2", 0, []string{"1:1 + 1", "2:2 + 2"}, nil},
+		{"blank and comments", "\n# a comment\n  \r\n 1 + 1 \n  # indented\n3\n", 0, []string{"4:1 + 1", "6:3"}, nil},
+		{"too long", "1 + 1\n22222222 + 2\n3\n", 5, []string{"1:1 + 1", "3:3"}, []int{2}},
+		{"too long at end", "1\n22222222", 5, []string{"1:1"}, []int{2}},
+		{"longer than buffer", strings.Repeat("1", 5000) + "\n2\n", 4096, []string{"2:2"}, []int{1}},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			lr := NewLineReader(strings.NewReader(d.in), d.maxLen)
+			var got []string
+			var errLines []int
+			for {
+				expression, line, err := lr.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					var le LineError
+					if !errors.As(err, &le) || !errors.Is(err, ErrLineTooLong) || le.Line != line {
+						t.Fatalf("unexpected error %v", err)
+					}
+					errLines = append(errLines, line)
+					continue
+				}
+				got = append(got, fmt.Sprintf("%d:%s", line, expression))
+			}
+			if strings.Join(got, "|") != strings.Join(d.expected, "|") {
+				t.Errorf("Expected %q, got %q", d.expected, got)
+			}
+			if len(errLines) != len(d.errLines) || (len(errLines) > 0 && errLines[0] != d.errLines[0]) {
+				t.Errorf("Expected errors on lines %v, got %v", d.errLines, errLines)
+			}
+		})
+	}
+}
+
+type noProgressReader struct{}
+
+func (noProgressReader) Read(p []byte) (int, error) {
+	return 0, nil
+}
+
+func TestLineReaderErrors(t *testing.T) {
+	errBroken := errors.New("broken pipe")
+	data := []struct {
+		name     string
+		r        io.Reader
+		expected error
+	}{
+		{"read error", io.MultiReader(strings.NewReader("1 + 1\n"), iotest.ErrReader(errBroken)), errBroken},
+		{"read error mid line", io.MultiReader(strings.NewReader("1 + 1\n2 +"), iotest.ErrReader(errBroken)), errBroken},
+		{"no progress", noProgressReader{}, io.ErrNoProgress},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			lr := NewLineReader(d.r, 0)
+			var err error
+			// A reader that never returns io.EOF must not make Next loop forever
+			for i := 0; i < 10 && err == nil; i++ {
+				_, _, err = lr.Next()
+			}
+			if !errors.Is(err, d.expected) {
+				t.Errorf("Expected %v, got %v", d.expected, err)
+			}
+			var le LineError
+			if !errors.As(err, &le) {
+				t.Errorf("Expected a LineError, got %T", err)
+			}
+		})
+	}
+}
+
+func TestProcessExpressionSharedReader(t *testing.T) {
+	p := Processor{LocalSolver{}}
+	input := "# totals\r\n1 + 1\r\n\r\n2 * 3\r\n"
+	data := []struct {
+		name string
+		r    io.Reader
+	}{
+		// strings.Reader can seek, so it's read with a buffer and given back what wasn't used
+		{"seeker", strings.NewReader(input)},
+		// MultiReader can't, so it's read one byte at a time
+		{"not_seeker", io.MultiReader(strings.NewReader(input))},
+		{"line_reader", p.Lines(strings.NewReader(input))},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			for _, expected := range []float64{2, 6} {
+				result, err := p.ProcessExpression(context.Background(), d.r)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if result != expected {
+					t.Errorf("Expected %g, got %g", expected, result)
+				}
+			}
+			if _, err := p.ProcessExpression(context.Background(), d.r); err != ErrNoExpression {
+				t.Errorf("Expected ErrNoExpression, got %v", err)
+			}
+		})
+	}
+
+	in := strings.NewReader("1 + 1\n2 * 3\n")
+	if _, err := p.ProcessExpression(context.Background(), in); err != nil {
+		t.Fatal(err)
+	}
+	if in.Len() != len("2 * 3\n") {
+		t.Errorf("Expected the rest of the input to be left unread, %d bytes are left", in.Len())
+	}
+
+	_, err := p.ProcessExpression(context.Background(), iotest.ErrReader(io.ErrUnexpectedEOF))
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected io.ErrUnexpectedEOF, got %v", err)
+	}
+}
+
+func TestProcessExpressionLineErrors(t *testing.T) {
+	p := Processor{LocalSolver{}}
+	lines := p.Lines(strings.NewReader("1 + 1\n\n# comment\n2 *\n1 / 0\n"))
+	if _, err := p.ProcessExpression(context.Background(), lines); err != nil {
+		t.Fatal(err)
+	}
+	_, err := p.ProcessExpression(context.Background(), lines)
+	var le LineError
+	if !errors.As(err, &le) || le.Line != 4 || !errors.Is(err, ErrInvalidExpression) {
+		t.Errorf("Expected an invalid expression on line 4, got %v", err)
+	}
+	_, err = p.ProcessExpression(context.Background(), lines)
+	if err == nil || err.Error() != "line 5: division by zero" || !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("Expected a division by zero on line 5, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package solver
+
+import (
+	"context"
+)
+
+// LocalSolver evaluates expressions in-process, see parser for the grammar. It supports
+// +, -, *, / and ^ with the usual precedence, parentheses, unary minus, decimal numbers
+// with an optional exponent like 1.5e-3, the constants pi and e and the built-in
+// functions sqrt, sin, cos, tan, exp, log, abs, pow, min and max. Each expression stands
+// on its own, so an assignment like x = 3 only returns 3; use a Session to keep variables
+// between expressions.
+type LocalSolver struct{}
+
+func (ls LocalSolver) Resolve(ctx context.Context, expression string) (float64, error) {
+	return evaluate(ctx, expression, env{})
+}
+
+// evaluate parses and evaluates expression, reading and assigning the variables in vars
+func evaluate(ctx context.Context, expression string, vars env) (float64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	n, err := parse(expression)
+	if err != nil {
+		// Same message as the math server used by RemoteSolver
+		return 0, ExpressionError{Expression: expression, Err: err}
+	}
+	return n.eval(vars)
+}
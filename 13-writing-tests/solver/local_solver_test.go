@@ -0,0 +1,82 @@
+package solver
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestLocalSolver_Resolve(t *testing.T) {
+	data := []struct {
+		name       string
+		expression string
+		result     float64
+		errMsg     string
+	}{
+		{"precedence", "2 + 2 * 10", 22, ""},
+		{"parentheses", "( 2 + 2 ) * 10", 40, ""},
+		{"left_associative", "10 - 4 - 3", 3, ""},
+		{"division", "7 / 2", 3.5, ""},
+		{"unary_minus", "-3 * -(2 + 1)", 9, ""},
+		{"floats", "0.5 + .25", 0.75, ""},
+		{"no_spaces", "(1+2)*(3+4)", 21, ""},
+		{"missing_paren", "( 2 + 2 * 10", 0, "invalid expression: ( 2 + 2 * 10"},
+		{"extra_paren", "2 + 2 )", 0, "invalid expression: 2 + 2 )"},
+		{"dangling_operator", "2 *", 0, "invalid expression: 2 *"},
+		{"bad_character", "2 $ 3", 0, "invalid expression: 2 $ 3"},
+		{"bad_number", "1.2.3", 0, "invalid expression: 1.2.3"},
+		{"empty", "", 0, "invalid expression: "},
+		{"division_by_zero", "1 / (2 - 2)", 0, "division by zero"},
+		{"exponent", "1e5 + 2.5E-1 + 3e+2", 100300.25, ""},
+		{"e_constant_after_number", "2 * e - 2e0 * e", 0, ""},
+		{"missing_exponent_digits", "1e", 0, "invalid expression: 1e"},
+		{"power", "2 ^ 10", 1024, ""},
+		{"power_right_associative", "2 ^ 3 ^ 2", 512, ""},
+		{"power_before_unary_minus", "-2 ^ 2", -4, ""},
+		{"negative_exponent", "2 ^ -1", 0.5, ""},
+		{"constants", "2 * pi - 2 * pi + e - e", 0, ""},
+		{"functions", "sqrt(16) + pow(2, 3) + abs(-1)", 13, ""},
+		{"variadic", "max(1, 7, 3) - min(4, 2, 9)", 5, ""},
+		{"nested_calls", "max(sqrt(9), log(exp(2)))", 3, ""},
+		{"assignment", "x = 2 * 3", 6, ""},
+		{"undefined_variable", "x + 1", 0, `undefined variable "x"`},
+		{"not_finite", "sqrt(-1)", 0, "result is not a finite number: sqrt(-1)"},
+		{"overflow", "1e300 * 1e300", 0, "result is not a finite number: 1e+300 * 1e+300"},
+		{"overflow_literal", "1e400 - 1", 0, "result is not a finite number: 1e400"},
+		{"underflow_literal", "1e-400 + 1", 1, ""},
+		{"overflow_sum", "1.5e308 + 1.5e308", 0, "result is not a finite number: 1.5e+308 + 1.5e+308"},
+		{"unknown_function", "foo(1)", 0, "invalid expression: foo(1)"},
+		{"wrong_arguments", "pow(2)", 0, "invalid expression: pow(2)"},
+		{"assign_constant", "pi = 3", 0, "invalid expression: pi = 3"},
+		{"missing_call_paren", "sqrt 4", 0, "invalid expression: sqrt 4"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			result, err := LocalSolver{}.Resolve(context.Background(), d.expression)
+			if result != d.result {
+				t.Errorf("expected `%f`, got `%f`", d.result, result)
+			}
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != d.errMsg {
+				t.Errorf("expected error `%s`, got `%s`", d.errMsg, errMsg)
+			}
+		})
+	}
+}
+
+func TestProcessorWithLocalSolver(t *testing.T) {
+	p := Processor{LocalSolver{}}
+	in := strings.NewReader("2 + 2 * 10\n( 2 + 2 ) * 10\n")
+	for _, expected := range []float64{22, 40} {
+		result, err := p.ProcessExpression(context.Background(), in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != expected {
+			t.Errorf("expected %f, got %f", expected, result)
+		}
+	}
+}
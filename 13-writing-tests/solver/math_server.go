@@ -0,0 +1,179 @@
+package solver
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxJSONBodyBytes limits the body of JSON requests when MaxExpressionBytes doesn't
+const maxJSONBodyBytes = 1 << 20
+
+// MathServer is the HTTP side of the protocol RemoteSolver speaks:
+// GET ?expression=... answers with the result as the body, or a 400 and the error message.
+// GET /derive?expression=...&variable=... answers with the derivative, see Derive.
+// POST requests with a JSON body use the JSON protocol described in protocol.go, other
+// POST bodies get a 415.
+// It's an http.Handler, so it can be run with http.Server or httptest.NewServer.
+type MathServer struct {
+	Solver MathSolver
+	// MaxExpressionBytes limits the length of the expression, 0 means no limit
+	MaxExpressionBytes int
+	// SolveTimeout limits how long each expression can take, 0 means no limit
+	SolveTimeout time.Duration
+	// MaxBatchSize limits how many expressions a batch can have, 0 means no limit
+	MaxBatchSize int
+}
+
+func (ms MathServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// POST is only part of the JSON protocol
+	if r.Method == http.MethodPost {
+		if !isJSON(r.Header.Get("Content-Type")) {
+			http.Error(w, "POST requests must have a Content-Type of application/json", http.StatusUnsupportedMediaType)
+			return
+		}
+		ms.serveJSON(w, r)
+		return
+	}
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	expression := r.URL.Query().Get("expression")
+	if ms.tooLong(expression) {
+		http.Error(w, ms.tooLongMessage(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, derivePath) {
+		derivative, err := derivative(expression, r.URL.Query().Get("variable"))
+		if err != nil {
+			status, _ := errorStatus(err)
+			w.WriteHeader(status)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		w.Write([]byte(derivative))
+		return
+	}
+	result, err := ms.solve(r.Context(), expression)
+	if err != nil {
+		status, _ := errorStatus(err)
+		w.WriteHeader(status)
+		w.Write([]byte(err.Error()))
+		return
+	}
+	w.Write([]byte(strconv.FormatFloat(result, 'f', -1, 64)))
+}
+
+func (ms MathServer) serveJSON(w http.ResponseWriter, r *http.Request) {
+	maxBody := int64(maxJSONBodyBytes)
+	if ms.MaxExpressionBytes > 0 && ms.MaxBatchSize > 0 {
+		// Leave room for the JSON around each expression, and for escaped characters
+		maxBody = int64(ms.MaxBatchSize) * (2*int64(ms.MaxExpressionBytes) + 64)
+	}
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBody))
+
+	if strings.HasSuffix(r.URL.Path, batchPath) {
+		var reqs []jsonRequest
+		if err := dec.Decode(&reqs); err != nil {
+			writeJSONError(w, http.StatusBadRequest, CodeInvalidRequest, "invalid batch: "+err.Error())
+			return
+		}
+		if ms.MaxBatchSize > 0 && len(reqs) > ms.MaxBatchSize {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, CodeInvalidRequest,
+				fmt.Sprintf("batch larger than %d expressions", ms.MaxBatchSize))
+			return
+		}
+		// Every expression gets its own answer, so the batch itself succeeds
+		resps := make([]jsonResponse, len(reqs))
+		for i, req := range reqs {
+			resps[i], _ = ms.solveJSON(r.Context(), req.Expression)
+		}
+		writeJSON(w, http.StatusOK, resps)
+		return
+	}
+
+	var req jsonRequest
+	if err := dec.Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, CodeInvalidRequest, "invalid request: "+err.Error())
+		return
+	}
+	if strings.HasSuffix(r.URL.Path, derivePath) {
+		if ms.tooLong(req.Expression) {
+			writeJSONError(w, http.StatusRequestEntityTooLarge, CodeExpressionTooLong, ms.tooLongMessage())
+			return
+		}
+		derivative, err := derivative(req.Expression, req.Variable)
+		if err != nil {
+			status, code := errorStatus(err)
+			writeJSONError(w, status, code, err.Error())
+			return
+		}
+		writeJSON(w, http.StatusOK, jsonResponse{Derivative: derivative})
+		return
+	}
+	resp, status := ms.solveJSON(r.Context(), req.Expression)
+	writeJSON(w, status, resp)
+}
+
+// derivative is the derivative of expression written back as an expression, the
+// variable is x by default
+func derivative(expression, variable string) (string, error) {
+	if variable == "" {
+		variable = "x"
+	}
+	n, err := Parse(expression)
+	if err != nil {
+		return "", err
+	}
+	d, err := Derive(n, variable)
+	if err != nil {
+		return "", err
+	}
+	return d.String(), nil
+}
+
+func (ms MathServer) solveJSON(ctx context.Context, expression string) (jsonResponse, int) {
+	if ms.tooLong(expression) {
+		return jsonResponse{Error: &jsonError{Code: CodeExpressionTooLong, Message: ms.tooLongMessage()}},
+			http.StatusRequestEntityTooLarge
+	}
+	result, err := ms.solve(ctx, expression)
+	if err != nil {
+		status, code := errorStatus(err)
+		return jsonResponse{Error: &jsonError{Code: code, Message: err.Error()}}, status
+	}
+	return jsonResponse{Result: &result}, http.StatusOK
+}
+
+func (ms MathServer) solve(ctx context.Context, expression string) (float64, error) {
+	if ms.SolveTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, ms.SolveTimeout)
+		defer cancel()
+	}
+	return ms.Solver.Resolve(ctx, expression)
+}
+
+func (ms MathServer) tooLong(expression string) bool {
+	return ms.MaxExpressionBytes > 0 && len(expression) > ms.MaxExpressionBytes
+}
+
+func (ms MathServer) tooLongMessage() string {
+	return fmt.Sprintf("expression longer than %d bytes", ms.MaxExpressionBytes)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", contentTypeJSON)
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, jsonResponse{Error: &jsonError{Code: code, Message: message}})
+}
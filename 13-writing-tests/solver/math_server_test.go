@@ -0,0 +1,173 @@
+package solver
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type slowSolver struct{}
+
+func (slowSolver) Resolve(ctx context.Context, expression string) (float64, error) {
+	select {
+	case <-time.After(time.Second):
+		return 1, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func TestMathServer(t *testing.T) {
+	server := httptest.NewServer(MathServer{Solver: LocalSolver{}, MaxExpressionBytes: 20})
+	defer server.Close()
+	data := []struct {
+		name       string
+		method     string
+		expression string
+		code       int
+		body       string
+	}{
+		{"ok", http.MethodGet, "( 2 + 2 ) * 10", http.StatusOK, "40"},
+		{"float", http.MethodGet, "1 / 4", http.StatusOK, "0.25"},
+		{"overflow", http.MethodGet, "1e300 * 1e300", http.StatusBadRequest, "result is not a finite number: 1e+300 * 1e+300"},
+		{"invalid", http.MethodGet, "( 2 + 2 * 10", http.StatusBadRequest, "invalid expression: ( 2 + 2 * 10"},
+		{"too_long", http.MethodGet, strings.Repeat("1+", 20) + "1", http.StatusRequestEntityTooLarge, "expression longer than 20 bytes\n"},
+		{"wrong_method", http.MethodPut, "1", http.StatusMethodNotAllowed, "method not allowed\n"},
+		{"post_not_json", http.MethodPost, "1", http.StatusUnsupportedMediaType,
+			"POST requests must have a Content-Type of application/json\n"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			req, err := http.NewRequest(d.method, server.URL+"?expression="+url.QueryEscape(d.expression), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != d.code || string(body) != d.body {
+				t.Errorf("expected %d `%s`, got %d `%s`", d.code, d.body, resp.StatusCode, body)
+			}
+		})
+	}
+}
+
+func TestMathServerTimeout(t *testing.T) {
+	rec := httptest.NewRecorder()
+	ms := MathServer{Solver: slowSolver{}, SolveTimeout: 10 * time.Millisecond}
+	ms.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/?expression=1", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected %d, got %d", http.StatusServiceUnavailable, rec.Code)
+	}
+}
+
+func TestMathServerJSON(t *testing.T) {
+	server := httptest.NewServer(MathServer{Solver: LocalSolver{}, MaxExpressionBytes: 20})
+	defer server.Close()
+	data := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		code        int
+		response    string
+	}{
+		{"ok", "/", "application/json", `{"expression": "( 2 + 2 ) * 10"}`, http.StatusOK, `{"result":40}`},
+		{"zero", "/", "application/json; charset=utf-8", `{"expression": "1 - 1"}`, http.StatusOK, `{"result":0}`},
+		{"invalid", "/", "application/json", `{"expression": "( 2"}`, http.StatusBadRequest,
+			`{"error":{"code":"invalid_expression","message":"invalid expression: ( 2"}}`},
+		{"division_by_zero", "/", "application/json", `{"expression": "1 / 0"}`, http.StatusBadRequest,
+			`{"error":{"code":"division_by_zero","message":"division by zero"}}`},
+		{"overflow", "/", "application/json", `{"expression": "1e300 * 1e300"}`, http.StatusBadRequest,
+			`{"error":{"code":"not_finite","message":"result is not a finite number: 1e+300 * 1e+300"}}`},
+		{"too_long", "/", "application/json", `{"expression": "` + strings.Repeat("1+", 20) + `1"}`, http.StatusRequestEntityTooLarge,
+			`{"error":{"code":"expression_too_long","message":"expression longer than 20 bytes"}}`},
+		{"bad_json", "/", "application/json", `{"expression": `, http.StatusBadRequest,
+			`{"error":{"code":"invalid_request","message":"invalid request: unexpected EOF"}}`},
+		{"not_json", "/", "text/plain", `{"expression": "1"}`, http.StatusUnsupportedMediaType, ""},
+		{"batch_not_json", "/batch", "text/plain", `[{"expression": "1"}]`, http.StatusUnsupportedMediaType, ""},
+		{"batch", "/batch", "application/json", `[{"expression": "1 + 1"}, {"expression": "2 *"}]`, http.StatusOK,
+			`[{"result":2},{"error":{"code":"invalid_expression","message":"invalid expression: 2 *"}}]`},
+		{"empty_batch", "/batch", "application/json", `[]`, http.StatusOK, `[]`},
+		{"bad_batch", "/batch", "application/json", `{"expression": "1"}`, http.StatusBadRequest, ""},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			resp, err := server.Client().Post(server.URL+d.path, d.contentType, strings.NewReader(d.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != d.code {
+				t.Errorf("expected %d, got %d `%s`", d.code, resp.StatusCode, body)
+			}
+			if d.response != "" && strings.TrimSpace(string(body)) != d.response {
+				t.Errorf("expected `%s`, got `%s`", d.response, body)
+			}
+		})
+	}
+}
+
+func TestMathServerDerive(t *testing.T) {
+	server := httptest.NewServer(MathServer{Solver: LocalSolver{}, MaxExpressionBytes: 30})
+	defer server.Close()
+	data := []struct {
+		name string
+		req  func() (*http.Response, error)
+		code int
+		body string
+	}{
+		{"text", func() (*http.Response, error) {
+			return server.Client().Get(server.URL + "/derive?expression=" + url.QueryEscape("3 * x ^ 2 + x"))
+		}, http.StatusOK, "6 * x + 1"},
+		{"text_variable", func() (*http.Response, error) {
+			return server.Client().Get(server.URL + "/derive?variable=t&expression=" + url.QueryEscape("sin(t) * x"))
+		}, http.StatusOK, "cos(t) * x"},
+		{"text_invalid", func() (*http.Response, error) {
+			return server.Client().Get(server.URL + "/derive?expression=" + url.QueryEscape("x ^"))
+		}, http.StatusBadRequest, "invalid expression: x ^"},
+		{"json", func() (*http.Response, error) {
+			return server.Client().Post(server.URL+"/derive", "application/json",
+				strings.NewReader(`{"expression": "x * log(x)"}`))
+		}, http.StatusOK, `{"derivative":"log(x) + 1"}`},
+		{"json_not_differentiable", func() (*http.Response, error) {
+			return server.Client().Post(server.URL+"/derive", "application/json",
+				strings.NewReader(`{"expression": "min(x, 1)", "variable": "x"}`))
+		}, http.StatusBadRequest, `{"error":{"code":"not_differentiable","message":"min is not differentiable"}}`},
+		{"json_too_long", func() (*http.Response, error) {
+			return server.Client().Post(server.URL+"/derive", "application/json",
+				strings.NewReader(`{"expression": "`+strings.Repeat("x+", 20)+`x"}`))
+		}, http.StatusRequestEntityTooLarge, `{"error":{"code":"expression_too_long","message":"expression longer than 30 bytes"}}`},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			resp, err := d.req()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != d.code || strings.TrimSpace(string(body)) != d.body {
+				t.Errorf("expected %d `%s`, got %d `%s`", d.code, d.body, resp.StatusCode, body)
+			}
+		})
+	}
+}
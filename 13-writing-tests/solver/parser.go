@@ -0,0 +1,217 @@
+package solver
+
+import (
+	"fmt"
+)
+
+// parser is a recursive descent parser, each precedence level has its own method:
+//
+//	statement = name "=" expr | expr
+//	expr      = term { ("+" | "-") term }
+//	term      = unary { ("*" | "/") unary }
+//	unary     = "-" unary | "+" unary | power
+//	power     = primary [ "^" unary ]
+//	primary   = number | name | name "(" [ expr { "," expr } ] ")" | "(" expr ")"
+//
+// ^ is right associative and binds tighter than unary minus, so -2^2 is -4 and 2^3^2 is 512
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// Parse returns the syntax tree of expression, or an ExpressionError if it isn't valid
+func Parse(expression string) (Node, error) {
+	n, err := parse(expression)
+	if err != nil {
+		return nil, ExpressionError{Expression: expression, Err: err}
+	}
+	return n, nil
+}
+
+// parse returns a SyntaxError if expression isn't valid
+func parse(expression string) (Node, error) {
+	tokens, err := tokenize(expression)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	n, err := p.statement()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, p.unexpected(t)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isOperator(ops ...string) bool {
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return false
+	}
+	for _, op := range ops {
+		if t.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) unexpected(t token) error {
+	if t.kind == tokenEOF {
+		return SyntaxError{Pos: t.pos, Msg: "unexpected end of expression"}
+	}
+	return SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("unexpected %q", t.text)}
+}
+
+func (p *parser) statement() (Node, error) {
+	t := p.peek()
+	if t.kind != tokenIdent || p.tokens[p.pos+1].kind != tokenAssign {
+		return p.expr()
+	}
+	if _, ok := constants[t.text]; ok {
+		return nil, SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("cannot assign to constant %s", t.text)}
+	}
+	if _, ok := functions[t.text]; ok {
+		return nil, SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("cannot assign to function %s", t.text)}
+	}
+	p.next()
+	p.next()
+	x, err := p.expr()
+	if err != nil {
+		return nil, err
+	}
+	return Assign{Name: t.text, X: x}, nil
+}
+
+func (p *parser) expr() (Node, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("+", "-") {
+		op := p.next()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		left = Binary{Op: op.text[0], Pos: op.pos, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) term() (Node, error) {
+	left, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOperator("*", "/") {
+		op := p.next()
+		right, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		left = Binary{Op: op.text[0], Pos: op.pos, Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) unary() (Node, error) {
+	if p.isOperator("-") {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return Neg{X: x}, nil
+	}
+	if p.isOperator("+") {
+		p.next()
+		return p.unary()
+	}
+	return p.power()
+}
+
+func (p *parser) power() (Node, error) {
+	base, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOperator("^") {
+		return base, nil
+	}
+	op := p.next()
+	exponent, err := p.unary()
+	if err != nil {
+		return nil, err
+	}
+	return Binary{Op: '^', Pos: op.pos, Left: base, Right: exponent}, nil
+}
+
+func (p *parser) primary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenNumber:
+		return Number{Value: t.value, Text: t.text, Pos: t.pos}, nil
+	case tokenIdent:
+		if p.peek().kind == tokenLParen {
+			return p.call(t)
+		}
+		if _, ok := functions[t.text]; ok {
+			return nil, SyntaxError{Pos: t.pos, Msg: fmt.Sprintf("missing ( after function %s", t.text)}
+		}
+		return Var{Name: t.text, Pos: t.pos}, nil
+	case tokenLParen:
+		n, err := p.expr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, SyntaxError{Pos: closing.pos, Msg: "missing )"}
+		}
+		return n, nil
+	}
+	return nil, p.unexpected(t)
+}
+
+func (p *parser) call(name token) (Node, error) {
+	f, ok := functions[name.text]
+	if !ok {
+		return nil, SyntaxError{Pos: name.pos, Msg: fmt.Sprintf("unknown function %s", name.text)}
+	}
+	p.next()
+	var args []Node
+	if p.peek().kind != tokenRParen {
+		for {
+			arg, err := p.expr()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek().kind != tokenComma {
+				break
+			}
+			p.next()
+		}
+	}
+	if closing := p.next(); closing.kind != tokenRParen {
+		return nil, SyntaxError{Pos: closing.pos, Msg: "missing )"}
+	}
+	if err := f.checkArgs(name.text, len(args)); err != nil {
+		return nil, SyntaxError{Pos: name.pos, Msg: err.Error()}
+	}
+	return Call{Name: name.text, Pos: name.pos, Args: args}, nil
+}
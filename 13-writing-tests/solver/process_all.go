@@ -0,0 +1,170 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Result is the outcome of a single line processed by ProcessAll, Line starts at 1
+type Result struct {
+	Line       int
+	Expression string
+	Value      float64
+	Err        error
+}
+
+type processConfig struct {
+	workers       int
+	failFast      bool
+	maxLineLength int
+	sequential    bool
+}
+
+// Sequential is implemented by solvers whose results depend on the order the expressions
+// arrive in, like a Session. ProcessAll resolves the lines one at a time, in input order,
+// when Sequential returns true. A solver that wraps another one can forward the call.
+type Sequential interface {
+	Sequential() bool
+}
+
+// ProcessOption configures ProcessAll
+type ProcessOption func(*processConfig)
+
+// WithWorkers sets how many expressions are resolved at the same time, the default is 1
+func WithWorkers(n int) ProcessOption {
+	return func(pc *processConfig) {
+		pc.workers = max(1, n)
+	}
+}
+
+// WithFailFast stops processing at the first expression that fails, by default every
+// line is processed and each Result carries its own error
+func WithFailFast() ProcessOption {
+	return func(pc *processConfig) {
+		pc.failFast = true
+	}
+}
+
+// WithMaxLineLength sets the longest line accepted, longer lines fail with ErrLineTooLong.
+// The default is DefaultMaxLineLength.
+func WithMaxLineLength(n int) ProcessOption {
+	return func(pc *processConfig) {
+		pc.maxLineLength = n
+	}
+}
+
+// WithSequential resolves the lines one at a time in input order, whatever WithWorkers
+// says, for a Solver whose lines depend on each other but doesn't implement Sequential
+func WithSequential() ProcessOption {
+	return func(pc *processConfig) {
+		pc.sequential = true
+	}
+}
+
+type job struct {
+	line       int
+	expression string
+}
+
+// ProcessAll reads every line of r and resolves it with the Solver, skipping blank lines
+// and comments like a LineReader. The results are in input order. Lines over the max
+// length get a Result with ErrLineTooLong, any other read error stops processing.
+// If ctx is canceled, or a line fails with WithFailFast, it returns the results that
+// were completed until then along with the error.
+func (p Processor) ProcessAll(ctx context.Context, r io.Reader, opts ...ProcessOption) ([]Result, error) {
+	pc := processConfig{workers: 1}
+	for _, opt := range opts {
+		opt(&pc)
+	}
+	if s, ok := p.Solver.(Sequential); ok && s.Sequential() {
+		pc.sequential = true
+	}
+	if pc.sequential {
+		// A single worker takes the jobs in the order they are read
+		pc.workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var firstErr error
+	var once sync.Once
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	jobs := make(chan job)
+	results := make(chan Result)
+	var wg sync.WaitGroup
+	for i := 0; i < pc.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				v, err := p.Solver.Resolve(ctx, j.expression)
+				if err != nil && pc.failFast {
+					fail(LineError{Line: j.line, Err: err})
+				}
+				results <- Result{Line: j.line, Expression: j.expression, Value: v, Err: err}
+			}
+		}()
+	}
+	var out []Result
+	collected := make(chan struct{})
+	go func() {
+		for res := range results {
+			out = append(out, res)
+		}
+		close(collected)
+	}()
+
+	lr := NewLineReader(r, pc.maxLineLength)
+	var readErr error
+feed:
+	for {
+		expression, line, err := lr.Next()
+		if err == io.EOF {
+			break
+		}
+		if errors.Is(err, ErrLineTooLong) {
+			if pc.failFast {
+				fail(err)
+				break
+			}
+			results <- Result{Line: line, Err: err}
+			continue
+		}
+		if err != nil {
+			readErr = err
+			break
+		}
+		select {
+		case jobs <- job{line: line, expression: expression}:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	close(results)
+	<-collected
+
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Line < out[j].Line
+	})
+	if firstErr != nil {
+		return out, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return out, err
+	}
+	if readErr != nil {
+		return out, readErr
+	}
+	return out, nil
+}
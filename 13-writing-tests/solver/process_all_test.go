@@ -0,0 +1,122 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+// delaySolver waits for as many milliseconds as the result, so later lines can finish first
+type delaySolver struct {
+	active, maxActive atomic.Int32
+	mu                sync.Mutex
+	seen              []string
+}
+
+func (ds *delaySolver) Resolve(ctx context.Context, expression string) (float64, error) {
+	n := ds.active.Add(1)
+	defer ds.active.Add(-1)
+	for {
+		m := ds.maxActive.Load()
+		if n <= m || ds.maxActive.CompareAndSwap(m, n) {
+			break
+		}
+	}
+	ds.mu.Lock()
+	ds.seen = append(ds.seen, expression)
+	ds.mu.Unlock()
+	v, err := LocalSolver{}.Resolve(ctx, expression)
+	if err != nil {
+		return 0, err
+	}
+	select {
+	case <-time.After(time.Duration(v) * time.Millisecond):
+		return v, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
+func TestProcessAll(t *testing.T) {
+	in := "30\n\n20 + 5\n( 2\n10\n1\n"
+	ds := &delaySolver{}
+	results, err := Processor{ds}.ProcessAll(context.Background(), strings.NewReader(in), WithWorkers(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range results {
+		if r.Err != nil {
+			got = append(got, fmt.Sprintf("%d:err", r.Line))
+		} else {
+			got = append(got, fmt.Sprintf("%d:%g", r.Line, r.Value))
+		}
+	}
+	if fmt.Sprint(got) != "[1:30 3:25 4:err 5:10 6:1]" {
+		t.Errorf("unexpected results %v", got)
+	}
+	if m := ds.maxActive.Load(); m > 3 {
+		t.Errorf("expected at most 3 concurrent calls, got %d", m)
+	}
+}
+
+func TestProcessAllFailFast(t *testing.T) {
+	in := "1\n( 2\n" + strings.Repeat("50\n", 20)
+	ds := &delaySolver{}
+	results, err := Processor{ds}.ProcessAll(context.Background(), strings.NewReader(in),
+		WithWorkers(2), WithFailFast())
+	if err == nil || err.Error() != "line 2: invalid expression: ( 2" {
+		t.Errorf("expected the error on line 2, got %v", err)
+	}
+	if len(results) == 0 || len(ds.seen) > 5 {
+		t.Errorf("expected processing to stop early, %d results after %d calls", len(results), len(ds.seen))
+	}
+}
+
+func TestProcessAllCanceled(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	in := strings.Repeat("10\n", 100)
+	results, err := Processor{&delaySolver{}}.ProcessAll(ctx, strings.NewReader(in))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if len(results) >= 100 {
+		t.Errorf("expected processing to stop early, got %d results", len(results))
+	}
+}
+
+func TestProcessAllLongLines(t *testing.T) {
+	in := "1 + 1\r\n" + strings.Repeat("1 + ", 10) + "1\r\n# comment\r\n2 * 3\r\n"
+	results, err := Processor{LocalSolver{}}.ProcessAll(context.Background(), strings.NewReader(in), WithMaxLineLength(16))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 {
+		t.Fatalf("Expected 3 results, got %d", len(results))
+	}
+	if results[0].Value != 2 || results[2].Value != 6 || results[2].Line != 4 {
+		t.Errorf("Unexpected results %v", results)
+	}
+	if !errors.Is(results[1].Err, ErrLineTooLong) || results[1].Line != 2 {
+		t.Errorf("Expected ErrLineTooLong on line 2, got %v on line %d", results[1].Err, results[1].Line)
+	}
+
+	_, err = Processor{LocalSolver{}}.ProcessAll(context.Background(), strings.NewReader(in), WithMaxLineLength(16), WithFailFast())
+	if !errors.Is(err, ErrLineTooLong) {
+		t.Errorf("Expected ErrLineTooLong, got %v", err)
+	}
+
+	r := io.MultiReader(strings.NewReader("1 + 1\n"), iotest.ErrReader(io.ErrUnexpectedEOF))
+	results, err = Processor{LocalSolver{}}.ProcessAll(context.Background(), r)
+	if !errors.Is(err, io.ErrUnexpectedEOF) || len(results) != 1 {
+		t.Errorf("Expected io.ErrUnexpectedEOF after 1 result, got %v, %v", results, err)
+	}
+}
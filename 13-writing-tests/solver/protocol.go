@@ -0,0 +1,101 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// The JSON protocol is used for POST requests with a Content-Type of application/json.
+// A single expression is sent as {"expression": "1 + 1"} and answered with
+// {"result": 2} or {"error": {"code": "invalid_expression", "message": "..."}}.
+// A batch is POSTed to the /batch path as an array of requests and answered with an
+// array of responses in the same order. The /derive path takes a "variable" as well,
+// x by default, and answers with {"derivative": "2 * x"}.
+const (
+	contentTypeJSON = "application/json"
+	batchPath       = "/batch"
+	derivePath      = "/derive"
+)
+
+// Error codes used by the JSON protocol
+const (
+	CodeInvalidExpression = "invalid_expression"
+	CodeDivisionByZero    = "division_by_zero"
+	CodeUndefined         = "undefined_variable"
+	CodeNotFinite         = "not_finite"
+	CodeNotDifferentiable = "not_differentiable"
+	CodeExpressionTooLong = "expression_too_long"
+	CodeInvalidRequest    = "invalid_request"
+	CodeServerUnavailable = "server_unavailable"
+	CodeSolveFailed       = "solve_failed"
+)
+
+type jsonRequest struct {
+	Expression string `json:"expression"`
+	Variable   string `json:"variable,omitempty"`
+}
+
+type jsonError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+type jsonResponse struct {
+	// Result is a pointer so that a result of 0 is still sent
+	Result     *float64   `json:"result,omitempty"`
+	Derivative string     `json:"derivative,omitempty"`
+	Error      *jsonError `json:"error,omitempty"`
+}
+
+func isJSON(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), contentTypeJSON)
+}
+
+// errorStatus maps an error from a MathSolver to the status and code the server answers with
+func errorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, ErrInvalidExpression):
+		return http.StatusBadRequest, CodeInvalidExpression
+	case errors.Is(err, ErrDivisionByZero):
+		return http.StatusBadRequest, CodeDivisionByZero
+	case errors.Is(err, ErrUndefined):
+		return http.StatusBadRequest, CodeUndefined
+	case errors.Is(err, ErrNotFinite):
+		return http.StatusBadRequest, CodeNotFinite
+	case errors.Is(err, ErrNotDifferentiable):
+		return http.StatusBadRequest, CodeNotDifferentiable
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled),
+		errors.Is(err, ErrServerUnavailable):
+		return http.StatusServiceUnavailable, CodeServerUnavailable
+	}
+	return http.StatusBadRequest, CodeSolveFailed
+}
+
+// remoteError turns an error answer from the math server back into the error the
+// server's MathSolver returned, as far as that's possible
+func remoteError(statusCode int, code, message, expression string) error {
+	switch code {
+	case CodeInvalidExpression:
+		return ExpressionError{Expression: expression}
+	case CodeDivisionByZero:
+		return ErrDivisionByZero
+	}
+	return StatusError{StatusCode: statusCode, Code: code, Message: message}
+}
+
+// textErrorCode recognizes the errors of the plain text protocol by their message
+func textErrorCode(statusCode int, body, expression string) string {
+	if statusCode != http.StatusBadRequest {
+		return ""
+	}
+	switch body {
+	case ExpressionError{Expression: expression}.Error():
+		return CodeInvalidExpression
+	case ErrDivisionByZero.Error():
+		return CodeDivisionByZero
+	}
+	return ""
+}
@@ -1,20 +1,151 @@
 package solver
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"time"
 )
 
 type RemoteSolver struct {
 	MathServerURL string
 	Client        *http.Client
+	// Retry configures retries for server and network errors, the zero value makes a single attempt
+	Retry RetryPolicy
+	// AttemptTimeout limits each attempt, the ctx passed to Resolve still limits the whole call.
+	// 0 means no limit per attempt
+	AttemptTimeout time.Duration
+	// Breaker is optional, it can be shared by many RemoteSolvers calling the same server
+	Breaker *CircuitBreaker
+	// JSON switches Resolve to the JSON protocol, the default is the plain text one
+	JSON bool
+}
+
+// RetryPolicy waits BaseDelay before the second attempt and doubles the wait for every
+// attempt after that, up to MaxDelay. Each wait is randomized between half and all of
+// it, so clients that failed together don't retry together.
+type RetryPolicy struct {
+	// MaxAttempts includes the first attempt, 0 or 1 means no retries
+	MaxAttempts int
+	// BaseDelay defaults to 100ms
+	BaseDelay time.Duration
+	// MaxDelay defaults to 5s
+	MaxDelay time.Duration
+}
+
+func (rp RetryPolicy) delay(attempt int) time.Duration {
+	base, maxDelay := rp.BaseDelay, rp.MaxDelay
+	if base <= 0 {
+		base = 100 * time.Millisecond
+	}
+	if maxDelay <= 0 {
+		maxDelay = 5 * time.Second
+	}
+	d := base
+	for i := 1; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	d = min(d, maxDelay)
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// retryable reports whether err may go away by trying again: network errors, timeouts of
+// a single attempt and 5xx answers. A 4xx means the expression itself is the problem.
+func retryable(err error) bool {
+	var se StatusError
+	if errors.As(err, &se) {
+		return se.StatusCode >= http.StatusInternalServerError
+	}
+	var ue *url.Error
+	return errors.As(err, &ue) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, context.DeadlineExceeded)
 }
 
 func (rs RemoteSolver) Resolve(ctx context.Context, expression string) (float64, error) {
+	var result float64
+	err := rs.do(ctx, func(ctx context.Context) error {
+		var err error
+		if rs.JSON {
+			result, err = rs.attemptJSON(ctx, expression)
+		} else {
+			result, err = rs.attempt(ctx, expression)
+		}
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result, nil
+}
+
+// ResolveBatch solves all the expressions with a single request to the /batch path of the
+// JSON protocol, whether JSON is set or not. Each Result has its own error, Line is the
+// position of the expression in expressions starting at 1. The error is only set when the
+// batch as a whole failed.
+func (rs RemoteSolver) ResolveBatch(ctx context.Context, expressions []string) ([]Result, error) {
+	var results []Result
+	err := rs.do(ctx, func(ctx context.Context) error {
+		var err error
+		results, err = rs.attemptBatch(ctx, expressions)
+		return err
+	})
+	return results, err
+}
+
+// do calls attempt until it succeeds, following the retry policy and the circuit breaker
+func (rs RemoteSolver) do(ctx context.Context, attempt func(ctx context.Context) error) error {
+	attempts := max(1, rs.Retry.MaxAttempts)
+	var err error
+	for i := 1; i <= attempts; i++ {
+		if i > 1 {
+			select {
+			case <-time.After(rs.Retry.delay(i - 1)):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if rs.Breaker != nil {
+			if berr := rs.Breaker.Allow(); berr != nil {
+				return berr
+			}
+		}
+		attemptCtx := ctx
+		cancel := func() {}
+		if rs.AttemptTimeout > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, rs.AttemptTimeout)
+		}
+		err = attempt(attemptCtx)
+		cancel()
+		if rs.Breaker != nil {
+			if ctx.Err() != nil {
+				// The caller gave up, that says nothing about the server
+				rs.Breaker.abandon()
+			} else {
+				rs.Breaker.Record(!retryable(err))
+			}
+		}
+		// Don't retry when the caller gave up, only when a single attempt did
+		if !retryable(err) || ctx.Err() != nil {
+			break
+		}
+	}
+	var se StatusError
+	if retryable(err) && ctx.Err() == nil && !errors.As(err, &se) {
+		// Out of attempts because of network errors or timeouts
+		return fmt.Errorf("%w: %w", ErrServerUnavailable, err)
+	}
+	return err
+}
+
+func (rs RemoteSolver) attempt(ctx context.Context, expression string) (float64, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
 		rs.MathServerURL+"?expression="+url.QueryEscape(expression), nil)
 	if err != nil {
@@ -30,7 +161,8 @@ func (rs RemoteSolver) Resolve(ctx context.Context, expression string) (float64,
 		return 0, err
 	}
 	if resp.StatusCode != http.StatusOK {
-		return 0, errors.New(string(contents))
+		body := string(contents)
+		return 0, remoteError(resp.StatusCode, textErrorCode(resp.StatusCode, body, expression), body, expression)
 	}
 	result, err := strconv.ParseFloat(string(contents), 64)
 	if err != nil {
@@ -38,3 +170,87 @@ func (rs RemoteSolver) Resolve(ctx context.Context, expression string) (float64,
 	}
 	return result, nil
 }
+
+func (rs RemoteSolver) attemptJSON(ctx context.Context, expression string) (float64, error) {
+	contents, status, err := rs.postJSON(ctx, rs.MathServerURL, jsonRequest{Expression: expression})
+	if err != nil {
+		return 0, err
+	}
+	var resp jsonResponse
+	if err := json.Unmarshal(contents, &resp); err != nil {
+		return 0, err
+	}
+	if resp.Error != nil {
+		return 0, remoteError(status, resp.Error.Code, resp.Error.Message, expression)
+	}
+	if resp.Result == nil {
+		return 0, fmt.Errorf("math server answered with status %d and no result", status)
+	}
+	return *resp.Result, nil
+}
+
+func (rs RemoteSolver) attemptBatch(ctx context.Context, expressions []string) ([]Result, error) {
+	reqs := make([]jsonRequest, len(expressions))
+	for i, expression := range expressions {
+		reqs[i] = jsonRequest{Expression: expression}
+	}
+	contents, status, err := rs.postJSON(ctx, strings.TrimSuffix(rs.MathServerURL, "/")+batchPath, reqs)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		// The whole batch failed, the answer is a single error
+		var resp jsonResponse
+		if err := json.Unmarshal(contents, &resp); err != nil || resp.Error == nil {
+			return nil, StatusError{StatusCode: status, Message: string(contents)}
+		}
+		return nil, StatusError{StatusCode: status, Code: resp.Error.Code, Message: resp.Error.Message}
+	}
+	var resps []jsonResponse
+	if err := json.Unmarshal(contents, &resps); err != nil {
+		return nil, err
+	}
+	if len(resps) != len(expressions) {
+		return nil, fmt.Errorf("math server answered %d results for %d expressions", len(resps), len(expressions))
+	}
+	results := make([]Result, len(resps))
+	for i, resp := range resps {
+		results[i] = Result{Line: i + 1, Expression: expressions[i]}
+		switch {
+		case resp.Error != nil:
+			results[i].Err = remoteError(status, resp.Error.Code, resp.Error.Message, expressions[i])
+		case resp.Result == nil:
+			results[i].Err = errors.New("math server answered with no result")
+		default:
+			results[i].Value = *resp.Result
+		}
+	}
+	return results, nil
+}
+
+// postJSON sends body and returns the JSON answer with its status. An answer that isn't
+// JSON, like a 502 from a proxy in front of the math server, is returned as a StatusError.
+func (rs RemoteSolver) postJSON(ctx context.Context, target string, body any) ([]byte, int, error) {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return nil, 0, err
+	}
+	req.Header.Set("Content-Type", contentTypeJSON)
+	resp, err := rs.Client.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+	contents, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !isJSON(resp.Header.Get("Content-Type")) {
+		return nil, resp.StatusCode, StatusError{StatusCode: resp.StatusCode, Message: string(contents)}
+	}
+	return contents, resp.StatusCode, nil
+}
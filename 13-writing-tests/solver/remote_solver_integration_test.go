@@ -10,13 +10,23 @@ package solver
 import (
 	"context"
 	"net/http"
+	"net/http/httptest"
+	"os"
 	"testing"
 )
 
 func TestRemoteSolver_ResolveIntegration(t *testing.T) {
-	// Since this is an integration test we actually use a local running server to test
+	// Since this is an integration test we actually use a running server to test.
+	// Set MATH_SERVER_URL to use one started with `go run ./cmd/mathserver`, otherwise
+	// the same handler is started in-process so the test can run offline.
+	url := os.Getenv("MATH_SERVER_URL")
+	if url == "" {
+		server := httptest.NewServer(MathServer{Solver: LocalSolver{}})
+		defer server.Close()
+		url = server.URL
+	}
 	rs := RemoteSolver{
-		MathServerURL: "http://localhost:8080",
+		MathServerURL: url,
 		Client:        http.DefaultClient,
 	}
 	data := []struct {
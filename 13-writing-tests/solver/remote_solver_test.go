@@ -2,9 +2,12 @@ package solver
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestRemoteSolver_Resolve(t *testing.T) {
@@ -63,3 +66,142 @@ func TestRemoteSolver_Resolve(t *testing.T) {
 		})
 	}
 }
+
+// faultServer answers with the statuses in order, repeating the last one when it runs out.
+// A status of 0 drops the connection and -1 waits for the request to be canceled.
+func faultServer(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		n := int(calls.Add(1))
+		status := statuses[min(n, len(statuses))-1]
+		switch status {
+		case 0:
+			conn, _, err := rw.(http.Hijacker).Hijack()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			conn.Close()
+		case -1:
+			<-r.Context().Done()
+		case http.StatusOK:
+			rw.Write([]byte("4"))
+		case http.StatusBadRequest:
+			rw.WriteHeader(status)
+			rw.Write([]byte("invalid expression: " + r.URL.Query().Get("expression")))
+		default:
+			rw.WriteHeader(status)
+			rw.Write([]byte(http.StatusText(status)))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestRemoteSolverRetries(t *testing.T) {
+	retry := RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	data := []struct {
+		name     string
+		statuses []int
+		retry    RetryPolicy
+		timeout  time.Duration
+		result   float64
+		errMsg   string
+		calls    int32
+	}{
+		{"no retries by default", []int{500, 200}, RetryPolicy{}, 0, 0, "Internal Server Error", 1},
+		{"5xx then ok", []int{500, 503, 200}, retry, 0, 4, "", 3},
+		{"5xx every time", []int{502}, retry, 0, 0, "Bad Gateway", 3},
+		{"no retry on 400", []int{400, 200}, retry, 0, 0, "invalid expression: 2 +", 1},
+		{"dropped connection", []int{0, 200}, retry, 0, 4, "", 2},
+		{"attempt timeout", []int{-1, 200}, retry, 50 * time.Millisecond, 4, "", 2},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			server, calls := faultServer(t, d.statuses...)
+			rs := RemoteSolver{
+				MathServerURL:  server.URL,
+				Client:         server.Client(),
+				Retry:          d.retry,
+				AttemptTimeout: d.timeout,
+			}
+			result, err := rs.Resolve(context.Background(), "2 +")
+			if result != d.result {
+				t.Errorf("Expected `%f`, got `%f`", d.result, result)
+			}
+			var errMsg string
+			if err != nil {
+				errMsg = err.Error()
+			}
+			if errMsg != d.errMsg {
+				t.Errorf("Expected error `%s`, got `%s`", d.errMsg, errMsg)
+			}
+			if calls.Load() != d.calls {
+				t.Errorf("Expected %d calls, got %d", d.calls, calls.Load())
+			}
+		})
+	}
+}
+
+func TestRemoteSolverCanceled(t *testing.T) {
+	server, calls := faultServer(t, -1)
+	rs := RemoteSolver{
+		MathServerURL:  server.URL,
+		Client:         server.Client(),
+		Retry:          RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond},
+		AttemptTimeout: time.Second,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := rs.Resolve(ctx, "1")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("Expected no retries after the caller gave up, got %d calls", calls.Load())
+	}
+}
+
+func TestRemoteSolverCircuitBreaker(t *testing.T) {
+	server, calls := faultServer(t, 500, 500, 500, 200)
+	now := time.Now()
+	cb := &CircuitBreaker{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+		Now:              func() time.Time { return now },
+	}
+	rs := RemoteSolver{MathServerURL: server.URL, Client: server.Client(), Breaker: cb}
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := rs.Resolve(ctx, "1"); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Expected a server error, got %v", err)
+		}
+	}
+	if _, err := rs.Resolve(ctx, "1"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 2 {
+		t.Errorf("Expected the open breaker to skip the server, got %d calls", calls.Load())
+	}
+
+	// The trial call fails, so the breaker opens again
+	now = now.Add(time.Minute)
+	if _, err := rs.Resolve(ctx, "1"); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected a server error, got %v", err)
+	}
+	if _, err := rs.Resolve(ctx, "1"); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Expected ErrCircuitOpen, got %v", err)
+	}
+
+	// The next trial call succeeds and closes it
+	now = now.Add(time.Minute)
+	for i := 0; i < 2; i++ {
+		if result, err := rs.Resolve(ctx, "1"); err != nil || result != 4 {
+			t.Fatalf("Expected 4, got %f, %v", result, err)
+		}
+	}
+	if calls.Load() != 5 {
+		t.Errorf("Expected 5 calls, got %d", calls.Load())
+	}
+}
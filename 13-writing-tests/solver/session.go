@@ -0,0 +1,62 @@
+package solver
+
+import (
+	"context"
+	"sort"
+	"sync"
+)
+
+// Session is a MathSolver that remembers variables between expressions, so that
+// "x = 3" on one line can be followed by "x * 2" on the next. It evaluates like a
+// LocalSolver otherwise. A Session is safe to use from several goroutines, but the
+// result then depends on the order the expressions arrive in, which is why it implements
+// Sequential.
+type Session struct {
+	mu   sync.Mutex
+	vars env
+}
+
+func NewSession() *Session {
+	return &Session{vars: env{}}
+}
+
+func (s *Session) Resolve(ctx context.Context, expression string) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.vars == nil {
+		s.vars = env{}
+	}
+	return evaluate(ctx, expression, s.vars)
+}
+
+// Sequential always returns true, ProcessAll must keep the lines in order for a Session
+func (s *Session) Sequential() bool {
+	return true
+}
+
+// Var returns the value of a variable and whether it's been assigned
+func (s *Session) Var(name string) (float64, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v, ok := s.vars[name]
+	return v, ok
+}
+
+// Names returns the names of the assigned variables, sorted
+func (s *Session) Names() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	names := make([]string, 0, len(s.vars))
+	for name := range s.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Reset forgets all the variables
+func (s *Session) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.vars = env{}
+}
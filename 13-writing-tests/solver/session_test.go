@@ -0,0 +1,126 @@
+package solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSessionVariables(t *testing.T) {
+	p := Processor{NewSession()}
+	in := strings.NewReader("x = 3\ny = x ^ 2\ny - x\nx = x + 1\nx\n")
+	for _, expected := range []float64{3, 9, 6, 4, 4} {
+		result, err := p.ProcessExpression(context.Background(), in)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if result != expected {
+			t.Errorf("expected %g, got %g", expected, result)
+		}
+	}
+
+	s := p.Solver.(*Session)
+	if names := s.Names(); fmt.Sprint(names) != "[x y]" {
+		t.Errorf("expected [x y], got %v", names)
+	}
+	// A failed assignment leaves the variable as it was
+	if _, err := s.Resolve(context.Background(), "x = 1 / 0"); !errors.Is(err, ErrDivisionByZero) {
+		t.Errorf("expected ErrDivisionByZero, got %v", err)
+	}
+	if x, ok := s.Var("x"); !ok || x != 4 {
+		t.Errorf("expected x to still be 4, got %g", x)
+	}
+	s.Reset()
+	if _, err := s.Resolve(context.Background(), "x"); !errors.Is(err, ErrUndefined) {
+		t.Errorf("expected ErrUndefined after Reset, got %v", err)
+	}
+}
+
+// wrappedSolver stands for a solver that adds something around another one, like logging
+type wrappedSolver struct {
+	MathSolver
+}
+
+// forwardingSolver is a wrapper that keeps the ordering of the solver it wraps
+type forwardingSolver struct {
+	wrappedSolver
+}
+
+func (fs forwardingSolver) Sequential() bool {
+	s, ok := fs.MathSolver.(Sequential)
+	return ok && s.Sequential()
+}
+
+func TestSessionProcessAll(t *testing.T) {
+	in := "a = 1\nb = a + 1\nc = a + b\nd = b + c\ne2 = c + d\n"
+	data := []struct {
+		name   string
+		solver MathSolver
+		opts   []ProcessOption
+	}{
+		// The workers are ignored, the lines depend on each other
+		{"session", NewSession(), []ProcessOption{WithWorkers(4)}},
+		{"forwarding_wrapper", forwardingSolver{wrappedSolver{NewSession()}}, []ProcessOption{WithWorkers(4)}},
+		{"with_sequential", wrappedSolver{NewSession()}, []ProcessOption{WithSequential(), WithWorkers(4)}},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			results, err := Processor{d.solver}.ProcessAll(context.Background(), strings.NewReader(in), d.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []float64
+			for _, r := range results {
+				if r.Err != nil {
+					t.Fatalf("line %d: %v", r.Line, r.Err)
+				}
+				got = append(got, r.Value)
+			}
+			if fmt.Sprint(got) != "[1 2 3 5 8]" {
+				t.Errorf("expected [1 2 3 5 8], got %v", got)
+			}
+		})
+	}
+}
+
+func TestErrorPositions(t *testing.T) {
+	data := []struct {
+		name       string
+		expression string
+		pos        int
+		syntax     bool
+	}{
+		{"bad_character", "2 $ 3", 2, true},
+		{"missing_paren", "2 * (3 + 4", 10, true},
+		{"unexpected", "1 + 2 )", 6, true},
+		{"number_too_large", "2 * 1e400", 4, false},
+		{"unknown_function", "1 + foo(2)", 4, true},
+		{"undefined_variable", "1 + 2 * y", 8, false},
+		{"division_by_zero", "4 / (2 - 2)", 2, false},
+		{"not_finite", "1 + log(0)", 4, false},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			_, err := LocalSolver{}.Resolve(context.Background(), d.expression)
+			var se SyntaxError
+			var ee EvalError
+			switch {
+			case d.syntax && errors.As(err, &se):
+				if !errors.Is(err, ErrInvalidExpression) {
+					t.Errorf("expected ErrInvalidExpression, got %v", err)
+				}
+				if se.Pos != d.pos {
+					t.Errorf("expected position %d, got %d: %v", d.pos, se.Pos, se)
+				}
+			case !d.syntax && errors.As(err, &ee):
+				if ee.Pos != d.pos {
+					t.Errorf("expected position %d, got %d: %v", d.pos, ee.Pos, ee)
+				}
+			default:
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package solver
+
+import (
+	"math"
+)
+
+// Simplify folds the constant parts of n and removes the operations that don't change
+// the result, so x * 1 + 0 becomes x and 2 * (3 * x) becomes 6 * x. Constants like pi
+// are kept as they are, and so are function calls that don't give a whole number, like
+// log(2), and constant parts that fail to evaluate, like 1 / 0.
+//
+// Simplify assumes the variables have values for which n can be evaluated, like a
+// denominator that isn't zero: 0 / x becomes 0 and x / x becomes 1, even though both
+// fail to evaluate for x = 0.
+func Simplify(n Node) Node {
+	switch n := n.(type) {
+	case Neg:
+		x := Simplify(n.X)
+		switch x := x.(type) {
+		case Number:
+			if finite(x.Value) {
+				return Number{Value: -x.Value}
+			}
+		case Neg:
+			return x.X
+		case Binary:
+			// -(2 * x) is -2 * x
+			if c, ok := x.Left.(Number); ok && x.Op == '*' {
+				return Binary{Op: '*', Pos: x.Pos, Left: Number{Value: -c.Value}, Right: x.Right}
+			}
+		}
+		return Neg{X: x}
+	case Binary:
+		return simplifyBinary(Binary{Op: n.Op, Pos: n.Pos, Left: Simplify(n.Left), Right: Simplify(n.Right)})
+	case Call:
+		args := make([]Node, len(n.Args))
+		constant := true
+		for i, arg := range n.Args {
+			args[i] = Simplify(arg)
+			_, ok := args[i].(Number)
+			constant = constant && ok
+		}
+		c := Call{Name: n.Name, Pos: n.Pos, Args: args}
+		// log(e) is 1, it shows up in the derivative of e ^ x
+		if c.Name == "log" && len(args) == 1 {
+			if v, ok := args[0].(Var); ok && v.Name == "e" {
+				return Number{Value: 1}
+			}
+		}
+		if constant {
+			if v, err := c.eval(nil); err == nil && v == math.Trunc(v) {
+				return Number{Value: v}
+			}
+		}
+		return c
+	case Assign:
+		return Assign{Name: n.Name, X: Simplify(n.X)}
+	}
+	return n
+}
+
+// simplifyBinary expects both sides of b to be simplified already
+func simplifyBinary(b Binary) Node {
+	l, lok := b.Left.(Number)
+	_, rok := b.Right.(Number)
+	if lok && rok {
+		if v, err := b.eval(nil); err == nil {
+			return Number{Value: v}
+		}
+		return b
+	}
+	isL := func(v float64) bool { return isNumber(b.Left, v) }
+	isR := func(v float64) bool { return isNumber(b.Right, v) }
+
+	switch b.Op {
+	case '+':
+		switch {
+		case isL(0):
+			return b.Right
+		case isR(0):
+			return b.Left
+		}
+		if neg, ok := b.Right.(Neg); ok {
+			return simplifyBinary(Binary{Op: '-', Pos: b.Pos, Left: b.Left, Right: neg.X})
+		}
+	case '-':
+		switch {
+		case isR(0):
+			return b.Left
+		case isL(0):
+			return Simplify(Neg{X: b.Right})
+		case b.Left.String() == b.Right.String():
+			return Number{Value: 0}
+		}
+		if neg, ok := b.Right.(Neg); ok {
+			return simplifyBinary(Binary{Op: '+', Pos: b.Pos, Left: b.Left, Right: neg.X})
+		}
+	case '*':
+		switch {
+		case isL(0), isR(0):
+			return Number{Value: 0}
+		case isL(1):
+			return b.Right
+		case isR(1):
+			return b.Left
+		case isL(-1):
+			return Simplify(Neg{X: b.Right})
+		case isR(-1):
+			return Simplify(Neg{X: b.Left})
+		case rok:
+			// Constants go first, x * 3 is 3 * x
+			return simplifyBinary(Binary{Op: '*', Pos: b.Pos, Left: b.Right, Right: b.Left})
+		}
+		// 2 * -x is -(2 * x), so that the sign ends up on the constant
+		if neg, ok := b.Right.(Neg); ok {
+			return Simplify(Neg{X: simplifyBinary(Binary{Op: '*', Pos: b.Pos, Left: b.Left, Right: neg.X})})
+		}
+		if neg, ok := b.Left.(Neg); ok {
+			return Simplify(Neg{X: simplifyBinary(Binary{Op: '*', Pos: b.Pos, Left: neg.X, Right: b.Right})})
+		}
+		// x * (1 / y) is x / y
+		if inner, ok := b.Right.(Binary); ok && inner.Op == '/' && isNumber(inner.Left, 1) {
+			return simplifyBinary(Binary{Op: '/', Pos: b.Pos, Left: b.Left, Right: inner.Right})
+		}
+		if inner, ok := b.Left.(Binary); ok && inner.Op == '/' && isNumber(inner.Left, 1) {
+			return simplifyBinary(Binary{Op: '/', Pos: b.Pos, Left: b.Right, Right: inner.Right})
+		}
+		// 2 * (3 * x) is 6 * x
+		if inner, ok := b.Right.(Binary); ok && lok && inner.Op == '*' {
+			if c, ok := inner.Left.(Number); ok {
+				return simplifyBinary(Binary{Op: '*', Pos: b.Pos, Left: Number{Value: l.Value * c.Value}, Right: inner.Right})
+			}
+		}
+	case '/':
+		switch {
+		case isR(0):
+			// Left for Eval to report the division by zero
+			return b
+		case isL(0):
+			return Number{Value: 0}
+		case isR(1):
+			return b.Left
+		case isR(-1):
+			return Simplify(Neg{X: b.Left})
+		case b.Left.String() == b.Right.String():
+			return Number{Value: 1}
+		}
+	case '^':
+		switch {
+		case isR(0), isL(1):
+			return Number{Value: 1}
+		case isR(1):
+			return b.Left
+		}
+	}
+	return b
+}
+
+func isNumber(n Node, v float64) bool {
+	num, ok := n.(Number)
+	return ok && num.Value == v
+}
@@ -14,32 +14,50 @@ type Processor struct {
 	Solver MathSolver
 }
 
-func (p Processor) ProcessExpression(ctx context.Context, r io.Reader) (float64, error) {
-	curExpression, err := readToNewLine(r)
-	if err != nil {
-		return 0, err
-	}
-	if len(curExpression) == 0 {
-		return 0, errors.New("no expression to read")
-	}
-	answer, err := p.Solver.Resolve(ctx, curExpression)
-	return answer, err
+var ErrNoExpression = errors.New("no expression to read")
+
+// Lines wraps r in a LineReader to pass to ProcessExpression. Reusing it for every call
+// keeps the buffer and the line count, so errors say which line of r they come from.
+func (p Processor) Lines(r io.Reader) *LineReader {
+	return NewLineReader(r, DefaultMaxLineLength)
 }
 
-func readToNewLine(r io.Reader) (string, error) {
-	var out []byte
-	b := make([]byte, 1)
-	for {
-		_, err := r.Read(b)
-		if err != nil {
-			if err == io.EOF {
-				return string(out), nil
+// ProcessExpression resolves the next expression in r. With a *LineReader from Lines, a
+// failed expression is returned in a LineError. Any other reader is wrapped in a
+// LineReader for this call only, so nothing past the expression may be consumed: when r
+// can seek, the input the buffer read ahead is given back, otherwise r is read one byte
+// at a time. Errors can't have line numbers then, since they'd start from 1 on each call.
+func (p Processor) ProcessExpression(ctx context.Context, r io.Reader) (float64, error) {
+	lr, persistent := r.(*LineReader)
+	var seeker io.Seeker
+	if !persistent {
+		// Files that are pipes or terminals are Seekers that fail, so check first
+		if s, ok := r.(io.Seeker); ok {
+			if _, err := s.Seek(0, io.SeekCurrent); err == nil {
+				seeker = s
 			}
 		}
-		if b[0] == '\n' {
-			break
+		if seeker != nil {
+			lr = NewLineReader(r, DefaultMaxLineLength)
+		} else {
+			lr = NewLineReader(oneByteReader{r}, DefaultMaxLineLength)
 		}
-		out = append(out, b[0])
 	}
-	return string(out), nil
+	expression, line, err := lr.Next()
+	if seeker != nil {
+		if _, serr := seeker.Seek(-int64(lr.r.Buffered()), io.SeekCurrent); serr != nil && err == nil {
+			err = serr
+		}
+	}
+	if err == io.EOF {
+		return 0, ErrNoExpression
+	}
+	if err != nil {
+		return 0, err
+	}
+	answer, err := p.Solver.Resolve(ctx, expression)
+	if err != nil && persistent {
+		return 0, LineError{Line: line, Err: err}
+	}
+	return answer, err
 }
@@ -0,0 +1,41 @@
+package main
+
+const crc32Poly = 0xedb88320
+
+var crc32Table = makeCRC32Table()
+
+func makeCRC32Table() [256]uint32 {
+	var t [256]uint32
+	for i := range t {
+		crc := uint32(i)
+		for j := 0; j < 8; j++ {
+			if crc&1 == 1 {
+				crc = (crc >> 1) ^ crc32Poly
+			} else {
+				crc >>= 1
+			}
+		}
+		t[i] = crc
+	}
+	return t
+}
+
+// checksumGo is the pure Go implementation, it's always compiled so the tests
+// can compare it with whichever Checksum was selected
+func checksumGo(b []byte) uint32 {
+	crc := ^uint32(0)
+	for _, v := range b {
+		crc = crc32Table[byte(crc)^v] ^ (crc >> 8)
+	}
+	return ^crc
+}
+
+// RecordsBytes lays out records back to back using the 16 byte wire format
+func RecordsBytes(records []Data) []byte {
+	out := make([]byte, 0, len(records)*16)
+	for _, d := range records {
+		b := BytesFromData(d)
+		out = append(out, b[:]...)
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+//go:build cgo
+
+package main
+
+/*
+#include <stddef.h>
+#include <stdint.h>
+
+static uint32_t crc32_table[256];
+
+static void crc32_init(void) {
+	for (uint32_t i = 0; i < 256; i++) {
+		uint32_t crc = i;
+		for (int j = 0; j < 8; j++) {
+			crc = (crc & 1) ? (crc >> 1) ^ 0xedb88320 : crc >> 1;
+		}
+		crc32_table[i] = crc;
+	}
+}
+
+static uint32_t crc32_ieee(const unsigned char *buf, size_t len) {
+	uint32_t crc = 0xffffffff;
+	for (size_t i = 0; i < len; i++) {
+		crc = crc32_table[(crc ^ buf[i]) & 0xff] ^ (crc >> 8);
+	}
+	return ~crc;
+}
+*/
+import "C"
+
+import "unsafe"
+
+// Cgo lets Go call C code. The C code goes in a comment right above import "C",
+// and its functions and types are accessed through the C pseudo-package.
+// Every call crosses from the Go stack to the C stack, which costs tens of
+// nanoseconds, so it's only worth it when the C side does a lot of work per call.
+
+const cgoEnabled = true
+
+func init() {
+	C.crc32_init()
+}
+
+// Checksum computes the CRC-32 (IEEE) of a buffer of Data records with the C function crc32_ieee.
+// With CGO_ENABLED=0 the build tags select the version in checksum_nocgo.go instead.
+// Both produce the same result as hash/crc32.ChecksumIEEE.
+func Checksum(b []byte) uint32 {
+	if len(b) == 0 {
+		return 0
+	}
+	// Passing a pointer to Go memory is allowed as long as that memory doesn't contain
+	// Go pointers and C doesn't keep it after the call returns
+	return uint32(C.crc32_ieee((*C.uchar)(unsafe.Pointer(&b[0])), C.size_t(len(b))))
+}
@@ -0,0 +1,12 @@
+//go:build !cgo
+
+package main
+
+const cgoEnabled = false
+
+// Checksum computes the CRC-32 (IEEE) of a buffer of Data records with checksumGo.
+// When cgo is available the build tags select the C version in checksum_cgo.go instead.
+// Both produce the same result as hash/crc32.ChecksumIEEE.
+func Checksum(b []byte) uint32 {
+	return checksumGo(b)
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"hash/crc32"
+	"testing"
+)
+
+func makeRecords(n int) []byte {
+	records := make([]Data, n)
+	for i := range records {
+		records[i] = inputData
+		records[i].Value = uint32(i)
+		records[i].Active = i%2 == 0
+	}
+	return RecordsBytes(records)
+}
+
+func TestChecksum(t *testing.T) {
+	t.Log("cgo enabled:", cgoEnabled)
+	data := [][]byte{nil, {}, input[:], makeRecords(3), makeRecords(1000)}
+	for _, d := range data {
+		expected := crc32.ChecksumIEEE(d)
+		if r := Checksum(d); r != expected {
+			t.Errorf("Checksum: expected %x, got %x", expected, r)
+		}
+		if r := checksumGo(d); r != expected {
+			t.Errorf("checksumGo: expected %x, got %x", expected, r)
+		}
+	}
+}
+
+var ch uint32
+
+// With a single record the cost is dominated by the cgo call overhead, run with
+// CGO_ENABLED=0 to compare against the pure Go version
+func BenchmarkChecksum(b *testing.B) {
+	for _, n := range []int{1, 64, 4096} {
+		buf := makeRecords(n)
+		b.Run(fmt.Sprintf("Checksum-%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ch = Checksum(buf)
+			}
+		})
+		b.Run(fmt.Sprintf("checksumGo-%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				ch = checksumGo(buf)
+			}
+		})
+	}
+}
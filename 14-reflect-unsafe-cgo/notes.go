@@ -179,6 +179,13 @@ Martha,37,"true"
 	//   It can also be converted to and from a special integer type, uintptr to do math with it,
 	//   this allows for individual byte extraction for any type, pointer arithmetic and byte manipulation
 
+	fmt.Println("-- Cgo --")
+	// Cgo lets Go call C functions (see checksum_cgo.go). It's only used when CGO_ENABLED=1,
+	// build tags select the pure Go version in checksum_nocgo.go otherwise.
+	// Calls into C are much slower than Go function calls, so use cgo to reuse existing
+	// C libraries rather than to make Go code faster.
+	records := RecordsBytes([]Data{{Value: 8675309, Label: [10]byte{'P', 'h', 'o', 'n', 'e'}, Active: true}})
+	fmt.Printf("cgo: %v, checksum: %x\n", cgoEnabled, Checksum(records))
 }
 
 func hasNoValue(i interface{}) bool {
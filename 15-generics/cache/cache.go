@@ -0,0 +1,318 @@
+// Package cache provides a size-bounded, concurrency-safe cache with LRU or LFU eviction,
+// per-entry expiration and a loader for misses.
+//
+// The loader has the same shape as MathSolver.Resolve, so a solver can be cached with:
+//
+//	c := cache.New(cache.Options[string, float64]{Capacity: 1000, Loader: solver.Resolve})
+//	result, err := c.GetOrLoad(ctx, "2 + 2 * 10")
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/dahc36/learning-go/15-generics/containers"
+)
+
+type Policy int
+
+const (
+	// LRU evicts the entry that was used least recently
+	LRU Policy = iota
+	// LFU evicts the entry that was used the fewest times, the least recently used on ties
+	LFU
+)
+
+type EvictionReason int
+
+const (
+	Capacity EvictionReason = iota
+	Expired
+)
+
+var ErrNoLoader = errors.New("cache: no loader configured")
+
+// PanicError is returned by GetOrLoad when the Loader panics, both to the caller that ran
+// it and to the ones waiting for it
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (pe PanicError) Error() string {
+	return fmt.Sprintf("cache: loader panicked: %v", pe.Value)
+}
+
+type Options[K comparable, V any] struct {
+	// Capacity is the maximum number of entries, 0 means unbounded
+	Capacity int
+	Policy   Policy
+	// TTL is how long entries live when they are added with Set or loaded, 0 means forever
+	TTL time.Duration
+	// Loader is called by GetOrLoad on a miss
+	Loader func(ctx context.Context, key K) (V, error)
+	// OnEvict is called after an entry is evicted, without holding the cache's lock
+	OnEvict func(key K, value V, reason EvictionReason)
+	// Now defaults to time.Now, tests can replace it to control expiration
+	Now func() time.Time
+}
+
+type Stats struct {
+	Hits, Misses, Evictions, Loads, LoadErrors uint64
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time
+	uses      uint64
+	lastUsed  uint64
+}
+
+// call is a load in progress, every GetOrLoad for the same key waits for done to be closed.
+// stale is set when a Set or Delete for the key happens during the load.
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+	stale bool
+}
+
+type eviction[K comparable, V any] struct {
+	key    K
+	value  V
+	reason EvictionReason
+}
+
+type Cache[K comparable, V any] struct {
+	opts     Options[K, V]
+	mu       sync.Mutex
+	entries  map[K]*containers.Item[*entry[K, V]]
+	order    *containers.PriorityQueue[*entry[K, V]]
+	inFlight map[K]*call[V]
+	tick     uint64
+	stats    Stats
+}
+
+func New[K comparable, V any](opts Options[K, V]) *Cache[K, V] {
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	// The entry at the front of the queue is the next one to be evicted
+	f := func(e1, e2 *entry[K, V]) int {
+		if opts.Policy == LFU && e1.uses != e2.uses {
+			if e1.uses < e2.uses {
+				return -1
+			}
+			return 1
+		}
+		if e1.lastUsed < e2.lastUsed {
+			return -1
+		}
+		return 1
+	}
+	return &Cache[K, V]{
+		opts:     opts,
+		entries:  map[K]*containers.Item[*entry[K, V]]{},
+		order:    containers.NewPriorityQueue(f),
+		inFlight: map[K]*call[V]{},
+	}
+}
+
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	v, ok, evicted := c.get(key)
+	c.mu.Unlock()
+	c.notify(evicted)
+	return v, ok
+}
+
+// GetOrLoad returns the cached value or calls the Loader. Concurrent misses for the same
+// key share a single call to the Loader, made with the ctx of the first caller. The other
+// callers stop waiting when their own ctx is done. Errors, including a PanicError when
+// the Loader panics, aren't cached. A Set or Delete made during the load wins over the
+// loaded value, which is then only returned to the callers that were already waiting.
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K) (V, error) {
+	c.mu.Lock()
+	v, ok, evicted := c.get(key)
+	if ok {
+		c.mu.Unlock()
+		c.notify(evicted)
+		return v, nil
+	}
+	if c.opts.Loader == nil {
+		c.mu.Unlock()
+		c.notify(evicted)
+		return v, ErrNoLoader
+	}
+	if cl, ok := c.inFlight[key]; ok {
+		c.mu.Unlock()
+		c.notify(evicted)
+		// Each caller can give up on its own, even if the load goes on
+		select {
+		case <-cl.done:
+			return cl.value, cl.err
+		case <-ctx.Done():
+			var zero V
+			return zero, ctx.Err()
+		}
+	}
+	cl := &call[V]{done: make(chan struct{})}
+	c.inFlight[key] = cl
+	c.mu.Unlock()
+	c.notify(evicted)
+
+	c.load(ctx, key, cl)
+	return cl.value, cl.err
+}
+
+// load runs the Loader for cl. The cleanup is deferred so that a panicking Loader still
+// removes the call and wakes up the callers waiting for it.
+func (c *Cache[K, V]) load(ctx context.Context, key K, cl *call[V]) {
+	var evicted []eviction[K, V]
+	defer func() {
+		if v := recover(); v != nil {
+			cl.err = PanicError{Value: v, Stack: debug.Stack()}
+		}
+		c.mu.Lock()
+		if c.inFlight[key] == cl {
+			delete(c.inFlight, key)
+		}
+		c.stats.Loads++
+		if cl.err != nil {
+			c.stats.LoadErrors++
+		} else if !cl.stale {
+			evicted = c.set(key, cl.value, c.opts.TTL)
+		}
+		c.mu.Unlock()
+		close(cl.done)
+		c.notify(evicted)
+	}()
+	cl.value, cl.err = c.opts.Loader(ctx, key)
+}
+
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.opts.TTL)
+}
+
+// SetWithTTL overrides the default TTL for this entry, 0 means it never expires
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	c.invalidate(key)
+	evicted := c.set(key, value, ttl)
+	c.mu.Unlock()
+	c.notify(evicted)
+}
+
+func (c *Cache[K, V]) Delete(key K) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidate(key)
+	item, ok := c.entries[key]
+	if ok {
+		c.remove(item)
+	}
+	return ok
+}
+
+// Len includes entries that expired but haven't been evicted yet
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+func (c *Cache[K, V]) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// The methods below must be called with c.mu held
+
+func (c *Cache[K, V]) get(key K) (V, bool, []eviction[K, V]) {
+	var zero V
+	item, ok := c.entries[key]
+	if !ok {
+		c.stats.Misses++
+		return zero, false, nil
+	}
+	e := item.Value
+	if c.expired(e) {
+		c.stats.Misses++
+		c.remove(item)
+		c.stats.Evictions++
+		return zero, false, []eviction[K, V]{{e.key, e.value, Expired}}
+	}
+	c.stats.Hits++
+	c.touch(item)
+	return e.value, true, nil
+}
+
+func (c *Cache[K, V]) set(key K, value V, ttl time.Duration) []eviction[K, V] {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.opts.Now().Add(ttl)
+	}
+	if item, ok := c.entries[key]; ok {
+		item.Value.value = value
+		item.Value.expiresAt = expiresAt
+		c.touch(item)
+		return nil
+	}
+	var evicted []eviction[K, V]
+	for c.opts.Capacity > 0 && len(c.entries) >= c.opts.Capacity {
+		e, _ := c.order.Peek()
+		reason := Capacity
+		if c.expired(e) {
+			reason = Expired
+		}
+		c.remove(c.entries[e.key])
+		c.stats.Evictions++
+		evicted = append(evicted, eviction[K, V]{e.key, e.value, reason})
+	}
+	c.tick++
+	e := &entry[K, V]{key: key, value: value, expiresAt: expiresAt, uses: 1, lastUsed: c.tick}
+	c.entries[key] = c.order.Push(e)
+	return evicted
+}
+
+// invalidate detaches the load in progress for key, if any, so that the value it loaded
+// before the key was changed isn't stored, and later misses start a new load
+func (c *Cache[K, V]) invalidate(key K) {
+	if cl, ok := c.inFlight[key]; ok {
+		cl.stale = true
+		delete(c.inFlight, key)
+	}
+}
+
+func (c *Cache[K, V]) touch(item *containers.Item[*entry[K, V]]) {
+	c.tick++
+	e := item.Value
+	e.uses++
+	e.lastUsed = c.tick
+	c.order.Update(item, e)
+}
+
+func (c *Cache[K, V]) remove(item *containers.Item[*entry[K, V]]) {
+	c.order.Remove(item)
+	delete(c.entries, item.Value.key)
+}
+
+func (c *Cache[K, V]) expired(e *entry[K, V]) bool {
+	return !e.expiresAt.IsZero() && !c.opts.Now().Before(e.expiresAt)
+}
+
+// notify calls OnEvict outside of the lock, so the callback can use the cache
+func (c *Cache[K, V]) notify(evicted []eviction[K, V]) {
+	if c.opts.OnEvict == nil {
+		return
+	}
+	for _, ev := range evicted {
+		c.opts.OnEvict(ev.key, ev.value, ev.reason)
+	}
+}
@@ -0,0 +1,287 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dahc36/learning-go/15-generics/cache"
+)
+
+func TestEvictionPolicies(t *testing.T) {
+	data := []struct {
+		name    string
+		policy  cache.Policy
+		evicted string
+	}{
+		// "a" was used last, "b" the least recently
+		{"lru", cache.LRU, "b"},
+		// "a" and "c" were used twice, "b" only once
+		{"lfu", cache.LFU, "b"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			var evicted []string
+			c := cache.New(cache.Options[string, int]{
+				Capacity: 3,
+				Policy:   d.policy,
+				OnEvict: func(k string, v int, reason cache.EvictionReason) {
+					evicted = append(evicted, k)
+				},
+			})
+			c.Set("a", 1)
+			c.Set("b", 2)
+			c.Set("c", 3)
+			c.Get("c")
+			c.Get("a")
+			c.Set("d", 4)
+			if fmt.Sprint(evicted) != "["+d.evicted+"]" {
+				t.Errorf("expected %s to be evicted, got %v", d.evicted, evicted)
+			}
+			if c.Len() != 3 {
+				t.Errorf("expected 3 entries, got %d", c.Len())
+			}
+		})
+	}
+}
+
+func TestLFUKeepsFrequentEntries(t *testing.T) {
+	c := cache.New(cache.Options[int, int]{Capacity: 2, Policy: cache.LFU})
+	c.Set(1, 1)
+	for i := 0; i < 5; i++ {
+		c.Get(1)
+	}
+	// A stream of new keys keeps replacing each other, but 1 stays
+	for i := 2; i < 10; i++ {
+		c.Set(i, i)
+	}
+	if _, ok := c.Get(1); !ok {
+		t.Error("expected the most used key to stay in the cache")
+	}
+}
+
+func TestTTL(t *testing.T) {
+	now := time.Now()
+	var reasons []cache.EvictionReason
+	c := cache.New(cache.Options[string, int]{
+		TTL: time.Minute,
+		Now: func() time.Time { return now },
+		OnEvict: func(k string, v int, reason cache.EvictionReason) {
+			reasons = append(reasons, reason)
+		},
+	})
+	c.Set("short", 1)
+	c.SetWithTTL("forever", 2, 0)
+	now = now.Add(30 * time.Second)
+	if _, ok := c.Get("short"); !ok {
+		t.Error("expected short to still be cached")
+	}
+	now = now.Add(time.Minute)
+	if _, ok := c.Get("short"); ok {
+		t.Error("expected short to be expired")
+	}
+	if _, ok := c.Get("forever"); !ok {
+		t.Error("expected forever to still be cached")
+	}
+	if len(reasons) != 1 || reasons[0] != cache.Expired {
+		t.Errorf("expected a single expiration, got %v", reasons)
+	}
+	if s := c.Stats(); s.Hits != 2 || s.Misses != 1 || s.Evictions != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestGetOrLoadSingleFlight(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := cache.New(cache.Options[string, float64]{
+		Capacity: 10,
+		Loader: func(ctx context.Context, expression string) (float64, error) {
+			calls.Add(1)
+			<-release
+			if expression == "bad" {
+				return 0, errors.New("invalid expression: bad")
+			}
+			return strconv.ParseFloat(expression, 64)
+		},
+	})
+
+	var wg sync.WaitGroup
+	results := make([]float64, 50)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := c.GetOrLoad(context.Background(), "42")
+			if err != nil {
+				t.Error(err)
+			}
+			results[i] = v
+		}(i)
+	}
+	// Give every goroutine time to find the load in progress
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("expected a single load, got %d", n)
+	}
+	for _, r := range results {
+		if r != 42 {
+			t.Fatalf("expected 42, got %f", r)
+		}
+	}
+	if _, err := c.GetOrLoad(context.Background(), "bad"); err == nil {
+		t.Error("expected the loader error")
+	}
+	if s := c.Stats(); s.Loads != 2 || s.LoadErrors != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+	if _, err := cache.New(cache.Options[int, int]{}).GetOrLoad(context.Background(), 1); !errors.Is(err, cache.ErrNoLoader) {
+		t.Errorf("expected ErrNoLoader, got %v", err)
+	}
+}
+
+func TestGetOrLoadPanic(t *testing.T) {
+	var calls atomic.Int32
+	release := make(chan struct{})
+	c := cache.New(cache.Options[string, int]{
+		Loader: func(ctx context.Context, key string) (int, error) {
+			if calls.Add(1) == 1 {
+				<-release
+				panic("loader bug")
+			}
+			return 1, nil
+		},
+	})
+
+	// A caller waiting for the load must be woken up with the panic as well
+	waiterErr := make(chan error)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		_, err := c.GetOrLoad(context.Background(), "k")
+		waiterErr <- err
+	}()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+	}()
+	_, err := c.GetOrLoad(context.Background(), "k")
+	var pe cache.PanicError
+	if !errors.As(err, &pe) || pe.Value != "loader bug" {
+		t.Errorf("expected a PanicError, got %v", err)
+	}
+	select {
+	case err := <-waiterErr:
+		if !errors.As(err, &pe) {
+			t.Errorf("expected the waiter to get a PanicError, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("the waiter is still blocked")
+	}
+
+	// The failed load isn't in flight anymore, so the next call loads again
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if v, err := c.GetOrLoad(context.Background(), "k"); err != nil || v != 1 {
+			t.Errorf("expected 1, got %d, %v", v, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("GetOrLoad after a panic is blocked")
+	}
+	if s := c.Stats(); s.Loads != 2 || s.LoadErrors != 1 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+}
+
+func TestGetOrLoadWaiterCanceled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	c := cache.New(cache.Options[string, int]{
+		Loader: func(ctx context.Context, key string) (int, error) {
+			<-release
+			return 1, nil
+		},
+	})
+	go c.GetOrLoad(context.Background(), "k")
+	time.Sleep(20 * time.Millisecond)
+
+	// The load is still going, but this caller stops waiting when its ctx is done
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := c.GetOrLoad(ctx, "k"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestGetOrLoadInvalidated(t *testing.T) {
+	data := []struct {
+		name     string
+		change   func(c *cache.Cache[string, int])
+		expected int
+		found    bool
+	}{
+		{"delete", func(c *cache.Cache[string, int]) { c.Delete("k") }, 0, false},
+		{"set", func(c *cache.Cache[string, int]) { c.Set("k", 10) }, 10, true},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			started := make(chan struct{})
+			release := make(chan struct{})
+			c := cache.New(cache.Options[string, int]{
+				Loader: func(ctx context.Context, key string) (int, error) {
+					close(started)
+					<-release
+					return 1, nil
+				},
+			})
+			loaded := make(chan int)
+			go func() {
+				v, _ := c.GetOrLoad(context.Background(), "k")
+				loaded <- v
+			}()
+			<-started
+			d.change(c)
+			close(release)
+			// The caller gets what was loaded, but the cache keeps the newer change
+			if v := <-loaded; v != 1 {
+				t.Errorf("expected the caller to get 1, got %d", v)
+			}
+			if v, ok := c.Get("k"); v != d.expected || ok != d.found {
+				t.Errorf("expected %d, %t, got %d, %t", d.expected, d.found, v, ok)
+			}
+		})
+	}
+}
+
+func BenchmarkCache(b *testing.B) {
+	for _, p := range []struct {
+		name   string
+		policy cache.Policy
+	}{{"LRU", cache.LRU}, {"LFU", cache.LFU}} {
+		b.Run(p.name, func(b *testing.B) {
+			c := cache.New(cache.Options[int, int]{Capacity: 1000, Policy: p.policy})
+			b.RunParallel(func(pb *testing.PB) {
+				// A few keys are requested much more often than the rest, like real traffic
+				z := rand.NewZipf(rand.New(rand.NewSource(1)), 1.1, 1, 10000)
+				for pb.Next() {
+					k := int(z.Uint64())
+					if _, ok := c.Get(k); !ok {
+						c.Set(k, k)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package containers
+
+// Deque is a double-ended queue backed by a circular buffer that grows as needed.
+// The zero value is an empty deque ready to use.
+type Deque[T any] struct {
+	vals  []T
+	head  int
+	count int
+}
+
+func (d *Deque[T]) Len() int {
+	return d.count
+}
+
+func (d *Deque[T]) PushBack(val T) {
+	d.grow()
+	d.vals[(d.head+d.count)%len(d.vals)] = val
+	d.count++
+}
+
+func (d *Deque[T]) PushFront(val T) {
+	d.grow()
+	d.head = (d.head - 1 + len(d.vals)) % len(d.vals)
+	d.vals[d.head] = val
+	d.count++
+}
+
+func (d *Deque[T]) PopFront() (T, bool) {
+	var zero T
+	if d.count == 0 {
+		return zero, false
+	}
+	val := d.vals[d.head]
+	// Clear the slot so the deque doesn't keep the value from being garbage collected
+	d.vals[d.head] = zero
+	d.head = (d.head + 1) % len(d.vals)
+	d.count--
+	return val, true
+}
+
+func (d *Deque[T]) PopBack() (T, bool) {
+	var zero T
+	if d.count == 0 {
+		return zero, false
+	}
+	i := (d.head + d.count - 1) % len(d.vals)
+	val := d.vals[i]
+	d.vals[i] = zero
+	d.count--
+	return val, true
+}
+
+func (d *Deque[T]) Front() (T, bool) {
+	return d.At(0)
+}
+
+func (d *Deque[T]) Back() (T, bool) {
+	return d.At(d.count - 1)
+}
+
+// At returns the value at position i, counting from the front
+func (d *Deque[T]) At(i int) (T, bool) {
+	if i < 0 || i >= d.count {
+		var zero T
+		return zero, false
+	}
+	return d.vals[(d.head+i)%len(d.vals)], true
+}
+
+func (d *Deque[T]) grow() {
+	if d.count < len(d.vals) {
+		return
+	}
+	vals := make([]T, max(8, 2*len(d.vals)))
+	for i := 0; i < d.count; i++ {
+		vals[i] = d.vals[(d.head+i)%len(d.vals)]
+	}
+	d.vals = vals
+	d.head = 0
+}
@@ -0,0 +1,51 @@
+package containers_test
+
+import (
+	"testing"
+
+	"github.com/dahc36/learning-go/15-generics/containers"
+)
+
+func TestDeque(t *testing.T) {
+	var d containers.Deque[int]
+	if _, ok := d.PopFront(); ok {
+		t.Fatal("expected PopFront on an empty deque to fail")
+	}
+	// Mixing both ends makes the buffer wrap around and grow a few times
+	for i := 0; i < 20; i++ {
+		d.PushBack(i)
+		d.PushFront(-i - 1)
+	}
+	if d.Len() != 40 {
+		t.Fatalf("expected Len 40, got %d", d.Len())
+	}
+	if v, _ := d.Front(); v != -20 {
+		t.Errorf("expected Front -20, got %d", v)
+	}
+	if v, _ := d.Back(); v != 19 {
+		t.Errorf("expected Back 19, got %d", v)
+	}
+	for i := -20; i < 20; i++ {
+		v, ok := d.PopFront()
+		if !ok || v != i {
+			t.Fatalf("expected %d, got %d", i, v)
+		}
+	}
+	d.PushBack(1)
+	d.PushBack(2)
+	if v, _ := d.PopBack(); v != 2 {
+		t.Errorf("expected PopBack 2, got %d", v)
+	}
+	if _, ok := d.At(1); ok {
+		t.Error("expected At(1) to be out of range")
+	}
+}
+
+func BenchmarkDeque(b *testing.B) {
+	var d containers.Deque[int]
+	for i := 0; i < b.N; i++ {
+		d.PushBack(i)
+		d.PushFront(i)
+		d.PopBack()
+	}
+}
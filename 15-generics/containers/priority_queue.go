@@ -0,0 +1,130 @@
+package containers
+
+// OrderableFunc has the same shape as the one used by Tree in package main, it returns
+// a negative number if v1 goes before v2, a positive one if it goes after, and 0 otherwise
+type OrderableFunc[T any] func(v1, v2 T) int
+
+// Item is a handle to a value in a PriorityQueue, used to update or remove it
+type Item[T any] struct {
+	Value T
+	index int
+}
+
+// PriorityQueue is a binary min-heap, Pop returns the value that goes first according
+// to its OrderableFunc. Use a function that inverts the result for a max-heap.
+type PriorityQueue[T any] struct {
+	f     OrderableFunc[T]
+	items []*Item[T]
+}
+
+func NewPriorityQueue[T any](f OrderableFunc[T]) *PriorityQueue[T] {
+	return &PriorityQueue[T]{
+		f: f,
+	}
+}
+
+func (pq *PriorityQueue[T]) Len() int {
+	return len(pq.items)
+}
+
+func (pq *PriorityQueue[T]) Push(val T) *Item[T] {
+	item := &Item[T]{Value: val, index: len(pq.items)}
+	pq.items = append(pq.items, item)
+	pq.up(item.index)
+	return item
+}
+
+func (pq *PriorityQueue[T]) Peek() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	return pq.items[0].Value, true
+}
+
+func (pq *PriorityQueue[T]) Pop() (T, bool) {
+	if len(pq.items) == 0 {
+		var zero T
+		return zero, false
+	}
+	item := pq.items[0]
+	pq.Remove(item)
+	return item.Value, true
+}
+
+// Update changes the value of an item that's still in the queue and moves it to its new position
+func (pq *PriorityQueue[T]) Update(item *Item[T], val T) bool {
+	if !pq.holds(item) {
+		return false
+	}
+	item.Value = val
+	pq.fix(item.index)
+	return true
+}
+
+// Remove takes an item out of the queue, it returns false if it was already removed
+func (pq *PriorityQueue[T]) Remove(item *Item[T]) bool {
+	if !pq.holds(item) {
+		return false
+	}
+	i, last := item.index, len(pq.items)-1
+	pq.swap(i, last)
+	pq.items[last] = nil
+	pq.items = pq.items[:last]
+	item.index = -1
+	if i < last {
+		pq.fix(i)
+	}
+	return true
+}
+
+func (pq *PriorityQueue[T]) holds(item *Item[T]) bool {
+	return item != nil && item.index >= 0 && item.index < len(pq.items) && pq.items[item.index] == item
+}
+
+func (pq *PriorityQueue[T]) less(i, j int) bool {
+	return pq.f(pq.items[i].Value, pq.items[j].Value) < 0
+}
+
+func (pq *PriorityQueue[T]) swap(i, j int) {
+	pq.items[i], pq.items[j] = pq.items[j], pq.items[i]
+	pq.items[i].index = i
+	pq.items[j].index = j
+}
+
+func (pq *PriorityQueue[T]) fix(i int) {
+	if !pq.down(i) {
+		pq.up(i)
+	}
+}
+
+func (pq *PriorityQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !pq.less(i, parent) {
+			break
+		}
+		pq.swap(i, parent)
+		i = parent
+	}
+}
+
+// down returns whether the item at i moved
+func (pq *PriorityQueue[T]) down(i int) bool {
+	start := i
+	for {
+		smallest := i
+		left, right := 2*i+1, 2*i+2
+		if left < len(pq.items) && pq.less(left, smallest) {
+			smallest = left
+		}
+		if right < len(pq.items) && pq.less(right, smallest) {
+			smallest = right
+		}
+		if smallest == i {
+			return i != start
+		}
+		pq.swap(i, smallest)
+		i = smallest
+	}
+}
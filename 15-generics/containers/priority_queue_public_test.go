@@ -0,0 +1,60 @@
+package containers_test
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/dahc36/learning-go/15-generics/containers"
+)
+
+func intOrder(v1, v2 int) int {
+	return v1 - v2
+}
+
+func TestPriorityQueue(t *testing.T) {
+	pq := containers.NewPriorityQueue(intOrder)
+	r := rand.New(rand.NewSource(1))
+	var expected []int
+	items := map[int]*containers.Item[int]{}
+	for i := 0; i < 100; i++ {
+		v := r.Intn(1000)
+		items[i] = pq.Push(v)
+		expected = append(expected, v)
+	}
+	// Move the first item to the front and remove the second one
+	pq.Update(items[0], -1)
+	expected[0] = -1
+	if !pq.Remove(items[1]) || pq.Remove(items[1]) {
+		t.Error("expected Remove to succeed only once")
+	}
+	expected = expected[1:]
+	expected[0] = -1
+	sort.Ints(expected)
+
+	if v, _ := pq.Peek(); v != -1 {
+		t.Errorf("expected Peek -1, got %d", v)
+	}
+	for _, e := range expected {
+		v, ok := pq.Pop()
+		if !ok || v != e {
+			t.Fatalf("expected %d, got %d", e, v)
+		}
+	}
+	if pq.Len() != 0 || pq.Update(items[2], 5) {
+		t.Error("expected an empty queue that can't update popped items")
+	}
+}
+
+func BenchmarkPriorityQueue(b *testing.B) {
+	pq := containers.NewPriorityQueue(intOrder)
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		pq.Push(r.Intn(1000))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		pq.Push(r.Intn(1000))
+		pq.Pop()
+	}
+}
@@ -0,0 +1,24 @@
+package containers
+
+// Queue is an unbounded first in, first out queue. Unlike Stack in package main it
+// doesn't need comparable, so it can hold funcs, slices and maps.
+// The zero value is an empty queue ready to use.
+type Queue[T any] struct {
+	d Deque[T]
+}
+
+func (q *Queue[T]) Push(val T) {
+	q.d.PushBack(val)
+}
+
+func (q *Queue[T]) Pop() (T, bool) {
+	return q.d.PopFront()
+}
+
+func (q *Queue[T]) Peek() (T, bool) {
+	return q.d.Front()
+}
+
+func (q *Queue[T]) Len() int {
+	return q.d.Len()
+}
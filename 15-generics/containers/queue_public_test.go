@@ -0,0 +1,38 @@
+package containers_test
+
+import (
+	"testing"
+
+	"github.com/dahc36/learning-go/15-generics/containers"
+)
+
+func TestQueue(t *testing.T) {
+	// Funcs aren't comparable, so they couldn't go in a Stack
+	var q containers.Queue[func() int]
+	for i := 0; i < 10; i++ {
+		i := i
+		q.Push(func() int { return i })
+	}
+	if f, _ := q.Peek(); f() != 0 {
+		t.Errorf("expected Peek to return the first value, got %d", f())
+	}
+	for i := 0; i < 10; i++ {
+		f, ok := q.Pop()
+		if !ok || f() != i {
+			t.Fatalf("expected %d, got %d", i, f())
+		}
+	}
+	if _, ok := q.Pop(); ok || q.Len() != 0 {
+		t.Error("expected the queue to be empty")
+	}
+}
+
+func BenchmarkQueue(b *testing.B) {
+	var q containers.Queue[int]
+	for i := 0; i < b.N; i++ {
+		q.Push(i)
+		if i%2 == 0 {
+			q.Pop()
+		}
+	}
+}
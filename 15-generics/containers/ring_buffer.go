@@ -0,0 +1,76 @@
+package containers
+
+// OverflowPolicy decides what a RingBuffer does when it's full
+type OverflowPolicy int
+
+const (
+	// Overwrite drops the oldest value to make room for the new one
+	Overwrite OverflowPolicy = iota
+	// Reject keeps the buffer as is and refuses the new value
+	Reject
+)
+
+// RingBuffer is a fixed-size first in, first out buffer
+type RingBuffer[T any] struct {
+	vals   []T
+	head   int
+	count  int
+	policy OverflowPolicy
+}
+
+func NewRingBuffer[T any](size int, policy OverflowPolicy) *RingBuffer[T] {
+	if size < 1 {
+		panic("ring buffer size must be at least 1")
+	}
+	return &RingBuffer[T]{
+		vals:   make([]T, size),
+		policy: policy,
+	}
+}
+
+// Push adds val to the buffer. It returns false if the buffer was full and val was rejected.
+func (rb *RingBuffer[T]) Push(val T) bool {
+	if rb.Full() {
+		if rb.policy == Reject {
+			return false
+		}
+		rb.vals[rb.head] = val
+		rb.head = (rb.head + 1) % len(rb.vals)
+		return true
+	}
+	rb.vals[(rb.head+rb.count)%len(rb.vals)] = val
+	rb.count++
+	return true
+}
+
+func (rb *RingBuffer[T]) Pop() (T, bool) {
+	var zero T
+	if rb.count == 0 {
+		return zero, false
+	}
+	val := rb.vals[rb.head]
+	rb.vals[rb.head] = zero
+	rb.head = (rb.head + 1) % len(rb.vals)
+	rb.count--
+	return val, true
+}
+
+func (rb *RingBuffer[T]) Peek() (T, bool) {
+	if rb.count == 0 {
+		var zero T
+		return zero, false
+	}
+	return rb.vals[rb.head], true
+}
+
+func (rb *RingBuffer[T]) Len() int {
+	return rb.count
+}
+
+func (rb *RingBuffer[T]) Cap() int {
+	return len(rb.vals)
+}
+
+func (rb *RingBuffer[T]) Full() bool {
+	return rb.count == len(rb.vals)
+}
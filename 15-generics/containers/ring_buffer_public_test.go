@@ -0,0 +1,47 @@
+package containers_test
+
+import (
+	"testing"
+
+	"github.com/dahc36/learning-go/15-generics/containers"
+)
+
+func TestRingBuffer(t *testing.T) {
+	data := []struct {
+		name     string
+		policy   containers.OverflowPolicy
+		accepted []bool
+		expected []int
+	}{
+		{"overwrite", containers.Overwrite, []bool{true, true, true, true, true}, []int{2, 3, 4}},
+		{"reject", containers.Reject, []bool{true, true, true, false, false}, []int{0, 1, 2}},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			rb := containers.NewRingBuffer[int](3, d.policy)
+			for i, accepted := range d.accepted {
+				if ok := rb.Push(i); ok != accepted {
+					t.Errorf("Push(%d): expected %v, got %v", i, accepted, ok)
+				}
+			}
+			if !rb.Full() || rb.Len() != rb.Cap() {
+				t.Error("expected the buffer to be full")
+			}
+			for _, e := range d.expected {
+				if v, _ := rb.Pop(); v != e {
+					t.Errorf("expected %d, got %d", e, v)
+				}
+			}
+			if _, ok := rb.Peek(); ok {
+				t.Error("expected the buffer to be empty")
+			}
+		})
+	}
+}
+
+func BenchmarkRingBuffer(b *testing.B) {
+	rb := containers.NewRingBuffer[int](1024, containers.Overwrite)
+	for i := 0; i < b.N; i++ {
+		rb.Push(i)
+	}
+}
@@ -0,0 +1,207 @@
+// Package graph provides a generic graph with traversals, topological sorting,
+// shortest paths, connected components and minimum spanning trees
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dahc36/learning-go/15-generics/containers"
+)
+
+// Weight is any number that can be added up and compared
+type Weight interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+type Edge[K comparable, W Weight] struct {
+	From, To K
+	Weight   W
+}
+
+// Graph stores its edges in adjacency lists. Nodes are kept in the order they were
+// added, so every algorithm returns the same result for the same graph.
+type Graph[K comparable, W Weight] struct {
+	directed bool
+	nodes    []K
+	adj      map[K][]Edge[K, W]
+	edges    []Edge[K, W]
+}
+
+func NewDirected[K comparable, W Weight]() *Graph[K, W] {
+	return &Graph[K, W]{directed: true, adj: map[K][]Edge[K, W]{}}
+}
+
+func NewUndirected[K comparable, W Weight]() *Graph[K, W] {
+	return &Graph[K, W]{adj: map[K][]Edge[K, W]{}}
+}
+
+var (
+	ErrUndirected     = errors.New("graph: not supported on undirected graphs")
+	ErrDirected       = errors.New("graph: not supported on directed graphs")
+	ErrNegativeWeight = errors.New("graph: negative edge weight")
+)
+
+// CycleError is returned by TopologicalSort, Cycle starts and ends with the same node
+type CycleError[K comparable] struct {
+	Cycle []K
+}
+
+func (ce CycleError[K]) Error() string {
+	parts := make([]string, len(ce.Cycle))
+	for i, k := range ce.Cycle {
+		parts[i] = fmt.Sprint(k)
+	}
+	return "graph: cycle " + strings.Join(parts, " -> ")
+}
+
+func (g *Graph[K, W]) Directed() bool {
+	return g.directed
+}
+
+func (g *Graph[K, W]) AddNode(k K) {
+	if _, ok := g.adj[k]; !ok {
+		g.adj[k] = nil
+		g.nodes = append(g.nodes, k)
+	}
+}
+
+// AddEdge adds both nodes if needed. Undirected edges can be followed both ways.
+func (g *Graph[K, W]) AddEdge(from, to K, w W) {
+	g.AddNode(from)
+	g.AddNode(to)
+	e := Edge[K, W]{From: from, To: to, Weight: w}
+	g.edges = append(g.edges, e)
+	g.adj[from] = append(g.adj[from], e)
+	if !g.directed && from != to {
+		g.adj[to] = append(g.adj[to], Edge[K, W]{From: to, To: from, Weight: w})
+	}
+}
+
+func (g *Graph[K, W]) Nodes() []K {
+	return slices.Clone(g.nodes)
+}
+
+// Edges returns every edge once, even for undirected graphs
+func (g *Graph[K, W]) Edges() []Edge[K, W] {
+	return slices.Clone(g.edges)
+}
+
+// Neighbors returns the edges leaving k
+func (g *Graph[K, W]) Neighbors(k K) []Edge[K, W] {
+	return slices.Clone(g.adj[k])
+}
+
+// BFS visits every node reachable from start in breadth-first order until visit returns false
+func (g *Graph[K, W]) BFS(start K, visit func(K) bool) {
+	if _, ok := g.adj[start]; !ok {
+		return
+	}
+	seen := map[K]bool{start: true}
+	var q containers.Queue[K]
+	q.Push(start)
+	for k, ok := q.Pop(); ok; k, ok = q.Pop() {
+		if !visit(k) {
+			return
+		}
+		for _, e := range g.adj[k] {
+			if !seen[e.To] {
+				seen[e.To] = true
+				q.Push(e.To)
+			}
+		}
+	}
+}
+
+// DFS visits every node reachable from start in depth-first order until visit returns false
+func (g *Graph[K, W]) DFS(start K, visit func(K) bool) {
+	if _, ok := g.adj[start]; !ok {
+		return
+	}
+	g.dfs(start, map[K]bool{}, visit)
+}
+
+func (g *Graph[K, W]) dfs(k K, seen map[K]bool, visit func(K) bool) bool {
+	seen[k] = true
+	if !visit(k) {
+		return false
+	}
+	for _, e := range g.adj[k] {
+		if !seen[e.To] && !g.dfs(e.To, seen, visit) {
+			return false
+		}
+	}
+	return true
+}
+
+// TopologicalSort orders the nodes so every edge goes from an earlier node to a later one.
+// If there's a cycle, it returns a CycleError with the nodes in it.
+func (g *Graph[K, W]) TopologicalSort() ([]K, error) {
+	if !g.directed {
+		return nil, ErrUndirected
+	}
+	const (
+		unvisited = iota
+		inProgress
+		done
+	)
+	state := map[K]int{}
+	var path []K
+	out := make([]K, 0, len(g.nodes))
+	var visit func(k K) error
+	visit = func(k K) error {
+		state[k] = inProgress
+		path = append(path, k)
+		for _, e := range g.adj[k] {
+			switch state[e.To] {
+			case inProgress:
+				// The cycle is the part of the current path starting at e.To
+				start := slices.Index(path, e.To)
+				cycle := append(slices.Clone(path[start:]), e.To)
+				return CycleError[K]{Cycle: cycle}
+			case unvisited:
+				if err := visit(e.To); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[k] = done
+		out = append(out, k)
+		return nil
+	}
+	for _, k := range g.nodes {
+		if state[k] == unvisited {
+			if err := visit(k); err != nil {
+				return nil, err
+			}
+		}
+	}
+	// Nodes were added after everything they point to, so the order is reversed
+	slices.Reverse(out)
+	return out, nil
+}
+
+// ConnectedComponents groups the nodes that can reach each other ignoring the direction
+// of the edges (the weakly connected components for a directed graph)
+func (g *Graph[K, W]) ConnectedComponents() [][]K {
+	uf := newUnionFind(g.nodes)
+	for _, e := range g.edges {
+		uf.union(e.From, e.To)
+	}
+	var out [][]K
+	index := map[K]int{}
+	for _, k := range g.nodes {
+		root := uf.find(k)
+		i, ok := index[root]
+		if !ok {
+			i = len(out)
+			index[root] = i
+			out = append(out, nil)
+		}
+		out[i] = append(out[i], k)
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package graph_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/dahc36/learning-go/15-generics/graph"
+)
+
+// orgChart has an edge from every manager to their reports
+func orgChart() *graph.Graph[string, int] {
+	g := graph.NewDirected[string, int]()
+	g.AddEdge("CEO", "CTO", 1)
+	g.AddEdge("CEO", "CFO", 1)
+	g.AddEdge("CTO", "Dev Lead", 1)
+	g.AddEdge("Dev Lead", "Dev", 1)
+	g.AddEdge("CFO", "Accountant", 1)
+	return g
+}
+
+func visited(traverse func(string, func(string) bool), start string) string {
+	var out []string
+	traverse(start, func(k string) bool {
+		out = append(out, k)
+		return true
+	})
+	return fmt.Sprint(out)
+}
+
+func TestTraversals(t *testing.T) {
+	g := orgChart()
+	if got := visited(g.BFS, "CEO"); got != "[CEO CTO CFO Dev Lead Accountant Dev]" {
+		t.Errorf("unexpected BFS order %s", got)
+	}
+	if got := visited(g.DFS, "CEO"); got != "[CEO CTO Dev Lead Dev CFO Accountant]" {
+		t.Errorf("unexpected DFS order %s", got)
+	}
+	if got := visited(g.BFS, "CFO"); got != "[CFO Accountant]" {
+		t.Errorf("expected BFS to only follow edges forward, got %s", got)
+	}
+}
+
+func TestTopologicalSort(t *testing.T) {
+	g := orgChart()
+	order, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pos := map[string]int{}
+	for i, k := range order {
+		pos[k] = i
+	}
+	for _, e := range g.Edges() {
+		if pos[e.From] > pos[e.To] {
+			t.Errorf("%s should come before %s in %v", e.From, e.To, order)
+		}
+	}
+
+	g.AddEdge("Dev", "CTO", 1)
+	_, err = g.TopologicalSort()
+	var ce graph.CycleError[string]
+	if !errors.As(err, &ce) || fmt.Sprint(ce.Cycle) != "[CTO Dev Lead Dev CTO]" {
+		t.Errorf("expected a cycle through the CTO, got %v", err)
+	}
+	if _, err := graph.NewUndirected[int, int]().TopologicalSort(); !errors.Is(err, graph.ErrUndirected) {
+		t.Errorf("expected ErrUndirected, got %v", err)
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	g := graph.NewDirected[string, float64]()
+	g.AddEdge("a", "b", 7)
+	g.AddEdge("a", "c", 9)
+	g.AddEdge("a", "f", 14)
+	g.AddEdge("b", "c", 10)
+	g.AddEdge("b", "d", 15)
+	g.AddEdge("c", "d", 11)
+	g.AddEdge("c", "f", 2)
+	g.AddEdge("d", "e", 6)
+	g.AddEdge("f", "e", 9)
+	path, d, ok, err := g.ShortestPath("a", "e")
+	if err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+	if fmt.Sprint(path) != "[a c f e]" || d != 20 {
+		t.Errorf("expected [a c f e] with length 20, got %v %f", path, d)
+	}
+	if _, _, ok, _ := g.ShortestPath("e", "a"); ok {
+		t.Error("expected a to be unreachable from e")
+	}
+	g.AddEdge("e", "a", -1)
+	if _, _, _, err := g.ShortestPath("a", "e"); !errors.Is(err, graph.ErrNegativeWeight) {
+		t.Errorf("expected ErrNegativeWeight, got %v", err)
+	}
+}
+
+func TestComponentsAndSpanningTree(t *testing.T) {
+	// Friendships go both ways
+	g := graph.NewUndirected[string, int]()
+	g.AddEdge("ana", "bob", 4)
+	g.AddEdge("bob", "cat", 1)
+	g.AddEdge("ana", "cat", 2)
+	g.AddEdge("cat", "dan", 5)
+	g.AddEdge("eve", "fay", 3)
+	g.AddNode("gus")
+	if got := fmt.Sprint(g.ConnectedComponents()); got != "[[ana bob cat dan] [eve fay] [gus]]" {
+		t.Errorf("unexpected components %s", got)
+	}
+	if got := visited(g.BFS, "dan"); got != "[dan cat bob ana]" {
+		t.Errorf("expected friends of friends of dan, got %s", got)
+	}
+	edges, total, err := g.MinimumSpanningTree()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 4 || total != 11 {
+		t.Errorf("expected 4 edges weighing 11, got %v %d", edges, total)
+	}
+	if _, _, err := orgChart().MinimumSpanningTree(); !errors.Is(err, graph.ErrDirected) {
+		t.Errorf("expected ErrDirected, got %v", err)
+	}
+}
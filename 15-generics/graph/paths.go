@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"slices"
+
+	"github.com/dahc36/learning-go/15-generics/containers"
+)
+
+type distance[K comparable, W Weight] struct {
+	node K
+	dist W
+}
+
+// Dijkstra returns the length of the shortest path from src to every reachable node,
+// and the previous node on each of those paths. Edge weights can't be negative.
+func (g *Graph[K, W]) Dijkstra(src K) (map[K]W, map[K]K, error) {
+	for _, e := range g.edges {
+		if e.Weight < 0 {
+			return nil, nil, ErrNegativeWeight
+		}
+	}
+	dist := map[K]W{}
+	prev := map[K]K{}
+	if _, ok := g.adj[src]; !ok {
+		return dist, prev, nil
+	}
+	pq := containers.NewPriorityQueue(func(d1, d2 distance[K, W]) int {
+		switch {
+		case d1.dist < d2.dist:
+			return -1
+		case d1.dist > d2.dist:
+			return 1
+		}
+		return 0
+	})
+	items := map[K]*containers.Item[distance[K, W]]{}
+	done := map[K]bool{}
+	dist[src] = 0
+	items[src] = pq.Push(distance[K, W]{src, 0})
+	for cur, ok := pq.Pop(); ok; cur, ok = pq.Pop() {
+		done[cur.node] = true
+		for _, e := range g.adj[cur.node] {
+			if done[e.To] {
+				continue
+			}
+			d := cur.dist + e.Weight
+			if old, seen := dist[e.To]; seen && old <= d {
+				continue
+			}
+			dist[e.To] = d
+			prev[e.To] = cur.node
+			// Update moves the node forward in the queue instead of adding it twice
+			if item, queued := items[e.To]; queued {
+				pq.Update(item, distance[K, W]{e.To, d})
+			} else {
+				items[e.To] = pq.Push(distance[K, W]{e.To, d})
+			}
+		}
+	}
+	return dist, prev, nil
+}
+
+// ShortestPath returns the nodes from src to dst along the shortest path and its length.
+// It returns false if dst can't be reached.
+func (g *Graph[K, W]) ShortestPath(src, dst K) ([]K, W, bool, error) {
+	dist, prev, err := g.Dijkstra(src)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	d, ok := dist[dst]
+	if !ok {
+		return nil, 0, false, nil
+	}
+	path := []K{dst}
+	for k := dst; k != src; {
+		k = prev[k]
+		path = append(path, k)
+	}
+	slices.Reverse(path)
+	return path, d, true, nil
+}
+
+// MinimumSpanningTree uses Kruskal's algorithm, taking the lightest edges that don't
+// close a cycle. For a graph with several components it returns a spanning forest.
+func (g *Graph[K, W]) MinimumSpanningTree() ([]Edge[K, W], W, error) {
+	if g.directed {
+		return nil, 0, ErrDirected
+	}
+	edges := slices.Clone(g.edges)
+	slices.SortStableFunc(edges, func(e1, e2 Edge[K, W]) int {
+		switch {
+		case e1.Weight < e2.Weight:
+			return -1
+		case e1.Weight > e2.Weight:
+			return 1
+		}
+		return 0
+	})
+	uf := newUnionFind(g.nodes)
+	var out []Edge[K, W]
+	var total W
+	for _, e := range edges {
+		if uf.union(e.From, e.To) {
+			out = append(out, e)
+			total += e.Weight
+		}
+	}
+	return out, total, nil
+}
+
+// unionFind tracks which nodes are already connected
+type unionFind[K comparable] struct {
+	parent map[K]K
+	rank   map[K]int
+}
+
+func newUnionFind[K comparable](nodes []K) *unionFind[K] {
+	uf := &unionFind[K]{parent: make(map[K]K, len(nodes)), rank: map[K]int{}}
+	for _, k := range nodes {
+		uf.parent[k] = k
+	}
+	return uf
+}
+
+func (uf *unionFind[K]) find(k K) K {
+	for uf.parent[k] != k {
+		// Path halving keeps the trees flat
+		uf.parent[k] = uf.parent[uf.parent[k]]
+		k = uf.parent[k]
+	}
+	return k
+}
+
+// union returns false if both nodes were already connected
+func (uf *unionFind[K]) union(a, b K) bool {
+	ra, rb := uf.find(a), uf.find(b)
+	if ra == rb {
+		return false
+	}
+	if uf.rank[ra] < uf.rank[rb] {
+		ra, rb = rb, ra
+	}
+	uf.parent[rb] = ra
+	if uf.rank[ra] == uf.rank[rb] {
+		uf.rank[ra]++
+	}
+	return true
+}
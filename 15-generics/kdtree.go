@@ -0,0 +1,147 @@
+package main
+
+import (
+	"math"
+	"slices"
+
+	"github.com/dahc36/learning-go/15-generics/containers"
+)
+
+// Spatial points can be split along each of their coordinates, which is what a KDTree needs
+// on top of Diff. Diff must never be smaller than the difference along a single coordinate,
+// which holds for the Euclidean distance used by Point2D and Point3D.
+type Spatial[T any] interface {
+	Differ[T]
+	Dims() int
+	Coord(i int) float64
+}
+
+type kdNode[T Spatial[T]] struct {
+	point       T
+	axis        int
+	left, right *kdNode[T]
+}
+
+// KDTree is a k-dimensional tree, every level splits the points in half along the next
+// coordinate, so searches can skip the half of the space that's too far away
+type KDTree[T Spatial[T]] struct {
+	root *kdNode[T]
+	size int
+}
+
+func NewKDTree[T Spatial[T]](points []T) *KDTree[T] {
+	return &KDTree[T]{
+		root: buildKD(slices.Clone(points), 0),
+		size: len(points),
+	}
+}
+
+func buildKD[T Spatial[T]](points []T, depth int) *kdNode[T] {
+	if len(points) == 0 {
+		return nil
+	}
+	axis := depth % points[0].Dims()
+	slices.SortFunc(points, func(p1, p2 T) int {
+		return BuiltInOrderable(p1.Coord(axis), p2.Coord(axis))
+	})
+	mid := len(points) / 2
+	return &kdNode[T]{
+		point: points[mid],
+		axis:  axis,
+		left:  buildKD(points[:mid], depth+1),
+		right: buildKD(points[mid+1:], depth+1),
+	}
+}
+
+func (t *KDTree[T]) Len() int {
+	return t.size
+}
+
+type neighbor[T any] struct {
+	point T
+	dist  float64
+}
+
+// Nearest returns the k points closest to p, closest first
+func (t *KDTree[T]) Nearest(p T, k int) []T {
+	if k <= 0 {
+		return nil
+	}
+	// A max-heap of the best k so far, so the worst of them is always at the top
+	best := containers.NewPriorityQueue(func(n1, n2 neighbor[T]) int {
+		return BuiltInOrderable(n2.dist, n1.dist)
+	})
+	t.root.nearest(p, k, best)
+	out := make([]T, best.Len())
+	for i := len(out) - 1; i >= 0; i-- {
+		n, _ := best.Pop()
+		out[i] = n.point
+	}
+	return out
+}
+
+func (n *kdNode[T]) nearest(p T, k int, best *containers.PriorityQueue[neighbor[T]]) {
+	if n == nil {
+		return
+	}
+	d := p.Diff(n.point)
+	if best.Len() < k {
+		best.Push(neighbor[T]{n.point, d})
+	} else if worst, _ := best.Peek(); d < worst.dist {
+		best.Pop()
+		best.Push(neighbor[T]{n.point, d})
+	}
+	delta := p.Coord(n.axis) - n.point.Coord(n.axis)
+	near, far := n.left, n.right
+	if delta > 0 {
+		near, far = far, near
+	}
+	near.nearest(p, k, best)
+	// The far side can only have closer points if the splitting plane is closer than the worst match
+	if worst, _ := best.Peek(); best.Len() < k || math.Abs(delta) < worst.dist {
+		far.nearest(p, k, best)
+	}
+}
+
+// Radius returns every point within r of p, in no particular order
+func (t *KDTree[T]) Radius(p T, r float64) []T {
+	var out []T
+	t.root.radius(p, r, &out)
+	return out
+}
+
+func (n *kdNode[T]) radius(p T, r float64, out *[]T) {
+	if n == nil {
+		return
+	}
+	if p.Diff(n.point) <= r {
+		*out = append(*out, n.point)
+	}
+	delta := p.Coord(n.axis) - n.point.Coord(n.axis)
+	if delta <= r {
+		n.left.radius(p, r, out)
+	}
+	if delta >= -r {
+		n.right.radius(p, r, out)
+	}
+}
+
+// ClosestPair finds the two closest points in the set by looking up the nearest
+// neighbour of every point. It returns false if there are fewer than two points.
+func ClosestPair[T Spatial[T]](points []T) (Pair[T], bool) {
+	if len(points) < 2 {
+		return Pair[T]{}, false
+	}
+	t := NewKDTree(points)
+	var closest Pair[T]
+	minDist := math.Inf(1)
+	for _, p := range points {
+		// The closest point to p is p itself, so we look at the second one
+		n := t.Nearest(p, 2)
+		if d := p.Diff(n[1]); d < minDist {
+			minDist = d
+			closest = Pair[T]{p, n[1]}
+		}
+	}
+	return closest, true
+}
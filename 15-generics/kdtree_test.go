@@ -0,0 +1,86 @@
+package main
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestFindCloserPair(t *testing.T) {
+	near := Pair[Point2D]{Point2D{0, 0}, Point2D{1, 1}}
+	far := Pair[Point2D]{Point2D{0, 0}, Point2D{10, 10}}
+	if r := FindCloserPair(far, near); r != near {
+		t.Errorf("expected %v, got %v", near, r)
+	}
+	if r := FindCloserPair(near, far); r != near {
+		t.Errorf("expected %v, got %v", near, r)
+	}
+}
+
+func randomPoints3D(r *rand.Rand, n int) []Point3D {
+	points := make([]Point3D, n)
+	for i := range points {
+		points[i] = Point3D{r.Float64() * 100, r.Float64() * 100, r.Float64() * 100}
+	}
+	return points
+}
+
+// bruteNearest sorts every point by its distance to p
+func bruteNearest[T Spatial[T]](points []T, p T) []T {
+	sorted := slices.Clone(points)
+	slices.SortStableFunc(sorted, func(p1, p2 T) int {
+		return BuiltInOrderable(p.Diff(p1), p.Diff(p2))
+	})
+	return sorted
+}
+
+func TestKDTreeAgainstBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	points := randomPoints3D(r, 500)
+	tree := NewKDTree(points)
+	for _, q := range randomPoints3D(r, 50) {
+		expected := bruteNearest(points, q)
+		for _, k := range []int{1, 5, 20} {
+			got := tree.Nearest(q, k)
+			for i := range got {
+				if q.Diff(got[i]) != q.Diff(expected[i]) {
+					t.Fatalf("Nearest(%v, %d)[%d]: expected %v, got %v", q, k, i, expected[i], got[i])
+				}
+			}
+		}
+
+		var within []Point3D
+		for _, p := range points {
+			if q.Diff(p) <= 15 {
+				within = append(within, p)
+			}
+		}
+		got := tree.Radius(q, 15)
+		if len(got) != len(within) {
+			t.Fatalf("Radius(%v): expected %d points, got %d", q, len(within), len(got))
+		}
+	}
+}
+
+func TestClosestPair(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	points := make([]Point2D, 300)
+	for i := range points {
+		points[i] = Point2D{r.Float64() * 1000, r.Float64() * 1000}
+	}
+	best := -1.0
+	for i := range points {
+		for j := i + 1; j < len(points); j++ {
+			if d := points[i].Diff(points[j]); best < 0 || d < best {
+				best = d
+			}
+		}
+	}
+	pair, ok := ClosestPair(points)
+	if !ok || pair.Val1.Diff(pair.Val2) != best {
+		t.Errorf("expected distance %f, got %v", best, pair)
+	}
+	if _, ok := ClosestPair(points[:1]); ok {
+		t.Error("expected no pair for a single point")
+	}
+}
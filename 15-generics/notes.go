@@ -152,6 +152,12 @@ func main() {
 	t1.Add(15)
 	fmt.Println(t1.Contains(15))
 	fmt.Println(t1.Contains(40))
+	t1.Remove(30)
+	fmt.Println(t1.Len())
+	it := t1.Iterator()
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		fmt.Println(v)
+	}
 
 	t2 := NewTree(Person.Order)
 	t2.Add(Person{"David", 34})
@@ -159,4 +165,23 @@ func main() {
 	t2.Add(Person{"Bob", 47})
 	fmt.Println(t2.Contains(Person{"Marla", 34}))
 	fmt.Println(t2.Contains(Person{"Fred", 20}))
+
+	// A type parameter can be constrained by a generic interface that refers to itself,
+	// that way any type with an Order method can be used without passing the function
+	t3 := NewOrderedTree[Person]()
+	t3.Add(Person{"Bob", 47})
+	fmt.Println(t3.Contains(Person{"Bob", 47}))
+	t4 := NewNaturalTree[string]()
+	t4.Add("b")
+	t4.Add("a")
+	fmt.Println(t4.Min())
+
+	// Generic types can be built on top of other generic types
+	buckets := NewTreeMap[int, string](BuiltInOrderable[int])
+	buckets.Put(0, "midnight")
+	buckets.Put(6, "morning")
+	buckets.Put(12, "afternoon")
+	buckets.Put(18, "evening")
+	_, bucket, _ := buckets.Floor(14)
+	fmt.Println(bucket) // afternoon
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// PanicError is returned when f panics inside one of the worker goroutines. A panic can't
+// be recovered from a different goroutine, so we recover it in the worker and pass it back.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (pe PanicError) Error() string {
+	return fmt.Sprintf("panic in worker: %v", pe.Value)
+}
+
+// failure keeps the first error reported by any worker and cancels the rest of the work
+type failure struct {
+	once   sync.Once
+	err    error
+	cancel context.CancelFunc
+}
+
+func (fl *failure) set(err error) {
+	fl.once.Do(func() {
+		fl.err = err
+		fl.cancel()
+	})
+}
+
+func (fl *failure) recover() {
+	if v := recover(); v != nil {
+		fl.set(PanicError{Value: v, Stack: debug.Stack()})
+	}
+}
+
+// ParallelMap is Map with a pool of workers. The results keep the order of s.
+// The first error or panic cancels the ctx passed to f and stops handing out new values.
+func ParallelMap[T1, T2 any](ctx context.Context, s []T1, workers int, f func(context.Context, T1) (T2, error)) ([]T2, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	fl := &failure{cancel: cancel}
+	r := make([]T2, len(s))
+	indexes := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < max(1, workers); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer fl.recover()
+			for i := range indexes {
+				// Every worker writes to a different index, so they don't need a lock
+				v, err := f(ctx, s[i])
+				if err != nil {
+					fl.set(err)
+					return
+				}
+				r[i] = v
+			}
+		}()
+	}
+
+feed:
+	for i := range s {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if fl.err != nil {
+		return nil, fl.err
+	}
+	// If no worker failed, the ctx can only be done because the caller canceled it
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// ParallelReduce splits s into one contiguous chunk per worker and reduces each of them
+// with f starting from initializer, then merges the partial results in order with combine.
+// For the result to match Reduce, initializer must not change the result when combined
+// (0 for sums, 1 for products) and combine must be associative.
+func ParallelReduce[T1, T2 any](ctx context.Context, s []T1, workers int, initializer T2, f func(T2, T1) T2, combine func(T2, T2) T2) (T2, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	fl := &failure{cancel: cancel}
+	workers = max(1, min(workers, len(s)))
+	partials := make([]T2, workers)
+	chunk := (len(s) + workers - 1) / workers
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			defer fl.recover()
+			r := initializer
+			for _, v := range s[min(w*chunk, len(s)):min((w+1)*chunk, len(s))] {
+				select {
+				case <-ctx.Done():
+					return
+				default:
+				}
+				r = f(r, v)
+			}
+			partials[w] = r
+		}(w)
+	}
+	wg.Wait()
+
+	if fl.err != nil {
+		return initializer, fl.err
+	}
+	if err := ctx.Err(); err != nil {
+		return initializer, err
+	}
+	r := partials[0]
+	for _, p := range partials[1:] {
+		r = combine(r, p)
+	}
+	return r, nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func fibonacci(n int) int {
+	if n < 2 {
+		return n
+	}
+	return fibonacci(n-1) + fibonacci(n-2)
+}
+
+func fibonacciCtx(ctx context.Context, n int) (int, error) {
+	return fibonacci(n), nil
+}
+
+func TestParallelMap(t *testing.T) {
+	s := []int{20, 1, 15, 8, 11, 3, 0, 18, 2}
+	expected := Map(s, fibonacci)
+	for _, workers := range []int{0, 1, 3, 20} {
+		r, err := ParallelMap(context.Background(), s, workers, fibonacciCtx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fmt.Sprint(r) != fmt.Sprint(expected) {
+			t.Errorf("%d workers: expected %v, got %v", workers, expected, r)
+		}
+	}
+}
+
+func TestParallelMapErrors(t *testing.T) {
+	boom := errors.New("boom")
+	data := []struct {
+		name    string
+		f       func(context.Context, int) (int, error)
+		isPanic bool
+	}{
+		{"error", func(ctx context.Context, i int) (int, error) {
+			if i == 5 {
+				return 0, boom
+			}
+			if i < 5 {
+				return i, nil
+			}
+			// The values handed out after 5 should see the ctx being canceled
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+				t.Error("expected the ctx to be canceled")
+			}
+			return i, nil
+		}, false},
+		{"panic", func(ctx context.Context, i int) (int, error) {
+			if i == 5 {
+				panic("boom")
+			}
+			return i, nil
+		}, true},
+	}
+	s := make([]int, 100)
+	for i := range s {
+		s[i] = i
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			r, err := ParallelMap(context.Background(), s, 4, d.f)
+			if r != nil {
+				t.Error("expected no results")
+			}
+			var pe PanicError
+			if d.isPanic && (!errors.As(err, &pe) || pe.Value != "boom") {
+				t.Errorf("expected a PanicError, got %v", err)
+			}
+			if !d.isPanic && !errors.Is(err, boom) {
+				t.Errorf("expected %v, got %v", boom, err)
+			}
+		})
+	}
+}
+
+func TestParallelMapCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := ParallelMap(ctx, []int{1, 2, 3}, 2, fibonacciCtx)
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestParallelReduce(t *testing.T) {
+	s := make([]int, 1001)
+	for i := range s {
+		s[i] = i
+	}
+	sum := func(total, v int) int { return total + v }
+	for _, workers := range []int{1, 3, 8, 2000} {
+		r, err := ParallelReduce(context.Background(), s, workers, 0, sum, sum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r != 500500 {
+			t.Errorf("%d workers: expected 500500, got %d", workers, r)
+		}
+	}
+	r, err := ParallelReduce(context.Background(), []int{}, 4, 7, sum, sum)
+	if err != nil || r != 7 {
+		t.Errorf("expected the initializer for an empty slice, got %d %v", r, err)
+	}
+	_, err = ParallelReduce(context.Background(), s, 4, 0, func(total, v int) int {
+		if v == 600 {
+			panic("boom")
+		}
+		return total + v
+	}, sum)
+	var pe PanicError
+	if !errors.As(err, &pe) || pe.Value != "boom" {
+		t.Errorf("expected a PanicError, got %v", err)
+	}
+}
+
+var parallelResult []int
+
+// With expensive values the parallel version wins once there's more than one worker,
+// with cheap ones the cost of the goroutines and channel dominates and Map is faster
+func BenchmarkParallelMap(b *testing.B) {
+	workloads := map[string]int{"cheap": 5, "expensive": 27}
+	for _, name := range []string{"cheap", "expensive"} {
+		s := make([]int, 64)
+		for i := range s {
+			s[i] = workloads[name]
+		}
+		b.Run(fmt.Sprintf("%s-sequential", name), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				parallelResult = Map(s, fibonacci)
+			}
+		})
+		for _, workers := range []int{1, 2, 4, 8} {
+			b.Run(fmt.Sprintf("%s-workers-%d", name, workers), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					r, err := ParallelMap(context.Background(), s, workers, fibonacciCtx)
+					if err != nil {
+						b.Fatal(err)
+					}
+					parallelResult = r
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+// PersistentTree is an immutable Tree. Add and Remove return a new tree and leave the
+// old one untouched, only the nodes along the path to the changed value are copied,
+// the rest are shared between both versions.
+// Since a version never changes after it's created, any number of goroutines can read
+// it without locks. Writers still need to publish new versions safely, for example
+// with an atomic.Pointer[PersistentTree[T]].
+type PersistentTree[T any] struct {
+	f    OrderableFunc[T]
+	root *Node[T]
+}
+
+func NewPersistentTree[T any](f OrderableFunc[T]) PersistentTree[T] {
+	return PersistentTree[T]{
+		f: f,
+	}
+}
+
+func (t PersistentTree[T]) Add(v T) PersistentTree[T] {
+	t.root = t.root.addCopy(t.f, v)
+	return t
+}
+
+// Remove returns the same tree if v isn't in it
+func (t PersistentTree[T]) Remove(v T) PersistentTree[T] {
+	t.root, _ = t.root.removeCopy(t.f, v)
+	return t
+}
+
+func (t PersistentTree[T]) Contains(v T) bool {
+	return t.root.Contains(t.f, v)
+}
+
+func (t PersistentTree[T]) Len() int {
+	return t.root.getSize()
+}
+
+func (t PersistentTree[T]) Min() (T, bool) {
+	return t.root.Min()
+}
+
+func (t PersistentTree[T]) Max() (T, bool) {
+	return t.root.Max()
+}
+
+func (t PersistentTree[T]) Walk(fn func(T) bool) {
+	t.root.Walk(fn)
+}
+
+func (t PersistentTree[T]) Iterator() *Iterator[T] {
+	return t.root.Iterator()
+}
+
+func (n *Node[T]) clone() *Node[T] {
+	c := *n
+	return &c
+}
+
+// addCopy works like Add, but copies every node it changes instead of modifying it
+func (n *Node[T]) addCopy(f OrderableFunc[T], v T) *Node[T] {
+	if n == nil {
+		return &Node[T]{value: v, height: 1, size: 1}
+	}
+	switch r := f(v, n.value); {
+	case r <= -1:
+		left := n.left.addCopy(f, v)
+		if left == n.left {
+			return n
+		}
+		n = n.clone()
+		n.left = left
+	case r >= 1:
+		right := n.right.addCopy(f, v)
+		if right == n.right {
+			return n
+		}
+		n = n.clone()
+		n.right = right
+	default:
+		return n
+	}
+	return n.rebalanceCopy()
+}
+
+func (n *Node[T]) removeCopy(f OrderableFunc[T], v T) (*Node[T], bool) {
+	if n == nil {
+		return nil, false
+	}
+	switch r := f(v, n.value); {
+	case r <= -1:
+		left, removed := n.left.removeCopy(f, v)
+		if !removed {
+			return n, false
+		}
+		n = n.clone()
+		n.left = left
+	case r >= 1:
+		right, removed := n.right.removeCopy(f, v)
+		if !removed {
+			return n, false
+		}
+		n = n.clone()
+		n.right = right
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		smallest, _ := n.right.Min()
+		right := n.right.removeMinCopy()
+		n = n.clone()
+		n.value = smallest
+		n.right = right
+	}
+	return n.rebalanceCopy(), true
+}
+
+func (n *Node[T]) removeMinCopy() *Node[T] {
+	if n.left == nil {
+		return n.right
+	}
+	left := n.left.removeMinCopy()
+	n = n.clone()
+	n.left = left
+	return n.rebalanceCopy()
+}
+
+// rebalanceCopy is rebalance for a node that was already copied. The rotations
+// modify the children as well, so those are copied before rotating.
+func (n *Node[T]) rebalanceCopy() *Node[T] {
+	n.update()
+	switch b := n.balance(); {
+	case b > 1:
+		n.left = n.left.clone()
+		if n.left.balance() < 0 {
+			n.left.right = n.left.right.clone()
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case b < -1:
+		n.right = n.right.clone()
+		if n.right.balance() > 0 {
+			n.right.left = n.right.left.clone()
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func treeValues[T any](t PersistentTree[T]) []T {
+	var out []T
+	t.Walk(func(v T) bool {
+		out = append(out, v)
+		return true
+	})
+	return out
+}
+
+func TestPersistentTreeSnapshots(t *testing.T) {
+	v0 := NewPersistentTree(BuiltInOrderable[int])
+	v1 := v0.Add(10).Add(20).Add(30)
+	v2 := v1.Add(15).Remove(20)
+	v3 := v2.Remove(100)
+
+	data := []struct {
+		name     string
+		tree     PersistentTree[int]
+		expected string
+	}{
+		{"empty", v0, "[]"},
+		{"v1", v1, "[10 20 30]"},
+		{"v2", v2, "[10 15 30]"},
+		{"remove_missing", v3, "[10 15 30]"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			if got := fmt.Sprint(treeValues(d.tree)); got != d.expected {
+				t.Errorf("expected %s, got %s", d.expected, got)
+			}
+		})
+	}
+	if v3.root != v2.root {
+		t.Error("removing a missing value should return the same tree")
+	}
+}
+
+func TestPersistentTreeRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tree := NewPersistentTree(BuiltInOrderable[int])
+	var versions []PersistentTree[int]
+	var expected []map[int]bool
+	present := map[int]bool{}
+	for i := 0; i < 2000; i++ {
+		v := r.Intn(300)
+		if r.Intn(3) == 0 {
+			tree = tree.Remove(v)
+			delete(present, v)
+		} else {
+			tree = tree.Add(v)
+			present[v] = true
+		}
+		if i%100 == 0 {
+			snapshot := map[int]bool{}
+			for k := range present {
+				snapshot[k] = true
+			}
+			versions = append(versions, tree)
+			expected = append(expected, snapshot)
+		}
+	}
+	// Every old version must still hold exactly the values it had when it was taken
+	for i, version := range versions {
+		checkNode(t, version.f, version.root)
+		if version.Len() != len(expected[i]) {
+			t.Fatalf("version %d: expected Len %d, got %d", i, len(expected[i]), version.Len())
+		}
+		for v := 0; v < 300; v++ {
+			if version.Contains(v) != expected[i][v] {
+				t.Fatalf("version %d: Contains(%d) expected %v", i, v, expected[i][v])
+			}
+		}
+	}
+}
+
+func TestPersistentTreeConcurrentReads(t *testing.T) {
+	var current atomic.Pointer[PersistentTree[int]]
+	tree := NewPersistentTree(BuiltInOrderable[int])
+	current.Store(&tree)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				snapshot := current.Load()
+				// A snapshot never changes, so it always has consecutive values from 0
+				if n := snapshot.Len(); n > 0 && !snapshot.Contains(n-1) {
+					t.Errorf("snapshot with %d values is missing %d", n, n-1)
+					return
+				}
+			}
+		}()
+	}
+	for i := 0; i < 1000; i++ {
+		next := current.Load().Add(i)
+		current.Store(&next)
+	}
+	wg.Wait()
+}
@@ -17,7 +17,7 @@ type Differ[T any] interface {
 
 func FindCloserPair[T Differ[T]](pair1, pair2 Pair[T]) Pair[T] {
 	d1 := pair1.Val1.Diff(pair1.Val2)
-	d2 := pair2.Val1.Diff(pair1.Val2)
+	d2 := pair2.Val1.Diff(pair2.Val2)
 	if d1 < d2 {
 		return pair1
 	}
@@ -38,6 +38,17 @@ func (p Point2D) Diff(from Point2D) float64 {
 	return math.Sqrt(x*x + y*y)
 }
 
+func (p Point2D) Dims() int {
+	return 2
+}
+
+func (p Point2D) Coord(i int) float64 {
+	if i == 0 {
+		return p.X
+	}
+	return p.Y
+}
+
 type Point3D struct {
 	X, Y, Z float64
 }
@@ -52,3 +63,17 @@ func (p Point3D) Diff(from Point3D) float64 {
 	z := p.Z - from.Z
 	return math.Sqrt(x*x + y*y + z*z)
 }
+
+func (p Point3D) Dims() int {
+	return 3
+}
+
+func (p Point3D) Coord(i int) float64 {
+	switch i {
+	case 0:
+		return p.X
+	case 1:
+		return p.Y
+	}
+	return p.Z
+}
@@ -0,0 +1,211 @@
+// Package seq provides lazy sequences. Unlike Map, Filter and Reduce in package main,
+// which build a full slice at every step, the operators here only wrap the previous
+// sequence, so values are produced one at a time as the terminal operation pulls them.
+package seq
+
+import (
+	"bufio"
+	"io"
+)
+
+// Seq is a pull-style sequence, every call returns the next value, or false once the
+// sequence is exhausted. A Seq can only be consumed once.
+type Seq[T any] func() (T, bool)
+
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~float32 | ~float64
+}
+
+type Pair[T1, T2 any] struct {
+	First  T1
+	Second T2
+}
+
+// -- Sources --
+
+func FromSlice[T any](s []T) Seq[T] {
+	i := 0
+	return func() (T, bool) {
+		if i >= len(s) {
+			var zero T
+			return zero, false
+		}
+		i++
+		return s[i-1], true
+	}
+}
+
+func FromChan[T any](ch <-chan T) Seq[T] {
+	return func() (T, bool) {
+		v, ok := <-ch
+		return v, ok
+	}
+}
+
+// Lines returns the lines of r without their line endings. The returned function
+// reports the read error, if any, once the sequence is exhausted.
+func Lines(r io.Reader) (Seq[string], func() error) {
+	scanner := bufio.NewScanner(r)
+	return func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		return scanner.Text(), true
+	}, scanner.Err
+}
+
+// Range returns start, start+step, ... up to but not including end.
+// A negative step counts down.
+func Range[T Number](start, end, step T) Seq[T] {
+	cur := start
+	return func() (T, bool) {
+		if step == 0 || (step > 0 && cur >= end) || (step < 0 && cur <= end) {
+			var zero T
+			return zero, false
+		}
+		v := cur
+		cur += step
+		return v, true
+	}
+}
+
+// -- Operators --
+
+func Map[T1, T2 any](s Seq[T1], f func(T1) T2) Seq[T2] {
+	return func() (T2, bool) {
+		v, ok := s()
+		if !ok {
+			var zero T2
+			return zero, false
+		}
+		return f(v), true
+	}
+}
+
+func Filter[T any](s Seq[T], f func(T) bool) Seq[T] {
+	return func() (T, bool) {
+		for {
+			v, ok := s()
+			if !ok || f(v) {
+				return v, ok
+			}
+		}
+	}
+}
+
+func Take[T any](s Seq[T], n int) Seq[T] {
+	return func() (T, bool) {
+		if n <= 0 {
+			var zero T
+			return zero, false
+		}
+		n--
+		return s()
+	}
+}
+
+func Skip[T any](s Seq[T], n int) Seq[T] {
+	return func() (T, bool) {
+		for ; n > 0; n-- {
+			if _, ok := s(); !ok {
+				break
+			}
+		}
+		return s()
+	}
+}
+
+// Chunk groups the values in slices of size n, the last one may be shorter
+func Chunk[T any](s Seq[T], n int) Seq[[]T] {
+	return func() ([]T, bool) {
+		var chunk []T
+		for len(chunk) < n {
+			v, ok := s()
+			if !ok {
+				break
+			}
+			chunk = append(chunk, v)
+		}
+		return chunk, len(chunk) > 0
+	}
+}
+
+// Zip pairs up the values of both sequences and stops when either of them ends
+func Zip[T1, T2 any](s1 Seq[T1], s2 Seq[T2]) Seq[Pair[T1, T2]] {
+	return func() (Pair[T1, T2], bool) {
+		v1, ok1 := s1()
+		if !ok1 {
+			return Pair[T1, T2]{}, false
+		}
+		v2, ok2 := s2()
+		if !ok2 {
+			return Pair[T1, T2]{}, false
+		}
+		return Pair[T1, T2]{v1, v2}, true
+	}
+}
+
+func FlatMap[T1, T2 any](s Seq[T1], f func(T1) Seq[T2]) Seq[T2] {
+	var inner Seq[T2]
+	return func() (T2, bool) {
+		for {
+			if inner != nil {
+				if v, ok := inner(); ok {
+					return v, true
+				}
+			}
+			v, ok := s()
+			if !ok {
+				var zero T2
+				return zero, false
+			}
+			inner = f(v)
+		}
+	}
+}
+
+// Distinct skips values that were already returned, it keeps every value it has seen in memory
+func Distinct[T comparable](s Seq[T]) Seq[T] {
+	seen := map[T]struct{}{}
+	return Filter(s, func(v T) bool {
+		if _, ok := seen[v]; ok {
+			return false
+		}
+		seen[v] = struct{}{}
+		return true
+	})
+}
+
+// -- Terminals --
+
+func Reduce[T1, T2 any](s Seq[T1], initializer T2, f func(T2, T1) T2) T2 {
+	r := initializer
+	for v, ok := s(); ok; v, ok = s() {
+		r = f(r, v)
+	}
+	return r
+}
+
+func Collect[T any](s Seq[T]) []T {
+	var out []T
+	for v, ok := s(); ok; v, ok = s() {
+		out = append(out, v)
+	}
+	return out
+}
+
+func ForEach[T any](s Seq[T], f func(T)) {
+	for v, ok := s(); ok; v, ok = s() {
+		f(v)
+	}
+}
+
+func First[T any](s Seq[T]) (T, bool) {
+	return s()
+}
+
+// Any stops pulling values as soon as one of them matches
+func Any[T any](s Seq[T], f func(T) bool) bool {
+	_, ok := Filter(s, f)()
+	return ok
+}
@@ -0,0 +1,132 @@
+package seq_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/dahc36/learning-go/15-generics/seq"
+)
+
+func TestOperators(t *testing.T) {
+	double := func(i int) int { return i * 2 }
+	even := func(i int) bool { return i%2 == 0 }
+	data := []struct {
+		name     string
+		s        seq.Seq[int]
+		expected string
+	}{
+		{"slice", seq.FromSlice([]int{1, 2, 3}), "[1 2 3]"},
+		{"range", seq.Range(0, 10, 3), "[0 3 6 9]"},
+		{"range_down", seq.Range(3, 0, -1), "[3 2 1]"},
+		{"map", seq.Map(seq.Range(0, 3, 1), double), "[0 2 4]"},
+		{"filter", seq.Filter(seq.Range(0, 7, 1), even), "[0 2 4 6]"},
+		{"take", seq.Take(seq.Range(0, 100, 1), 3), "[0 1 2]"},
+		{"skip", seq.Skip(seq.Range(0, 5, 1), 3), "[3 4]"},
+		{"skip_past_end", seq.Skip(seq.Range(0, 5, 1), 10), "[]"},
+		{"flat_map", seq.FlatMap(seq.Range(1, 4, 1), func(i int) seq.Seq[int] {
+			return seq.Range(0, i, 1)
+		}), "[0 0 1 0 1 2]"},
+		{"distinct", seq.Distinct(seq.FromSlice([]int{1, 2, 1, 3, 2})), "[1 2 3]"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			if got := fmt.Sprint(seq.Collect(d.s)); got != d.expected {
+				t.Errorf("expected %s, got %s", d.expected, got)
+			}
+		})
+	}
+}
+
+func TestChunkAndZip(t *testing.T) {
+	chunks := seq.Collect(seq.Chunk(seq.Range(0, 5, 1), 2))
+	if got := fmt.Sprint(chunks); got != "[[0 1] [2 3] [4]]" {
+		t.Errorf("unexpected chunks %s", got)
+	}
+	pairs := seq.Collect(seq.Zip(seq.FromSlice([]string{"a", "b", "c"}), seq.Range(1, 100, 1)))
+	if got := fmt.Sprint(pairs); got != "[{a 1} {b 2} {c 3}]" {
+		t.Errorf("unexpected pairs %s", got)
+	}
+}
+
+func TestTerminalsStopEarly(t *testing.T) {
+	pulled := 0
+	counting := seq.Map(seq.Range(0, 1000, 1), func(i int) int {
+		pulled++
+		return i
+	})
+	if !seq.Any(counting, func(i int) bool { return i == 5 }) {
+		t.Error("expected Any to find 5")
+	}
+	if pulled != 6 {
+		t.Errorf("expected Any to pull 6 values, pulled %d", pulled)
+	}
+	if v, ok := seq.First(seq.Filter(seq.Range(10, 20, 1), func(i int) bool { return i%7 == 0 })); !ok || v != 14 {
+		t.Errorf("expected First to be 14, got %d", v)
+	}
+}
+
+func TestSources(t *testing.T) {
+	ch := make(chan string, 2)
+	ch <- "x"
+	ch <- "y"
+	close(ch)
+	if got := seq.Reduce(seq.FromChan(ch), "", func(acc, v string) string { return acc + v }); got != "xy" {
+		t.Errorf("expected xy, got %s", got)
+	}
+
+	lines, errFn := seq.Lines(strings.NewReader("one\ntwo\r\nthree"))
+	if got := fmt.Sprint(seq.Collect(lines)); got != "[one two three]" {
+		t.Errorf("unexpected lines %s", got)
+	}
+	if errFn() != nil {
+		t.Error(errFn())
+	}
+
+	readErr := errors.New("boom")
+	lines, errFn = seq.Lines(iotest.ErrReader(readErr))
+	seq.Collect(lines)
+	if !errors.Is(errFn(), readErr) {
+		t.Errorf("expected the read error, got %v", errFn())
+	}
+}
+
+var blackHole int
+
+func BenchmarkLazyChain(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := seq.Range(0, 1_000_000, 1)
+		s = seq.Filter(seq.Map(s, func(v int) int { return v * 3 }), func(v int) bool { return v%2 == 0 })
+		blackHole = seq.Reduce(s, 0, func(acc, v int) int { return acc + v })
+	}
+}
+
+// BenchmarkSliceChain does the same work as BenchmarkLazyChain with intermediate slices,
+// like Map, Filter and Reduce in package main
+func BenchmarkSliceChain(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		s := make([]int, 1_000_000)
+		for j := range s {
+			s[j] = j
+		}
+		mapped := make([]int, len(s))
+		for j, v := range s {
+			mapped[j] = v * 3
+		}
+		var filtered []int
+		for _, v := range mapped {
+			if v%2 == 0 {
+				filtered = append(filtered, v)
+			}
+		}
+		total := 0
+		for _, v := range filtered {
+			total += v
+		}
+		blackHole = total
+	}
+}
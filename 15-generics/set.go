@@ -0,0 +1,170 @@
+package main
+
+import "slices"
+
+// Set is the map[T]struct{} set from chapter 3 made generic. Since it's a map, the zero
+// value is nil and must be created with NewSet or make before adding values.
+type Set[T comparable] map[T]struct{}
+
+func NewSet[T comparable](vals ...T) Set[T] {
+	s := make(Set[T], len(vals))
+	s.Add(vals...)
+	return s
+}
+
+func (s Set[T]) Add(vals ...T) {
+	for _, v := range vals {
+		s[v] = struct{}{}
+	}
+}
+
+func (s Set[T]) Remove(v T) {
+	delete(s, v)
+}
+
+func (s Set[T]) Contains(v T) bool {
+	_, ok := s[v]
+	return ok
+}
+
+func (s Set[T]) Len() int {
+	return len(s)
+}
+
+func (s Set[T]) Union(other Set[T]) Set[T] {
+	out := make(Set[T], len(s)+len(other))
+	for v := range s {
+		out[v] = struct{}{}
+	}
+	for v := range other {
+		out[v] = struct{}{}
+	}
+	return out
+}
+
+func (s Set[T]) Intersection(other Set[T]) Set[T] {
+	// Loop over the smaller set
+	if len(other) < len(s) {
+		s, other = other, s
+	}
+	out := Set[T]{}
+	for v := range s {
+		if other.Contains(v) {
+			out[v] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Difference returns the values in s that aren't in other
+func (s Set[T]) Difference(other Set[T]) Set[T] {
+	out := Set[T]{}
+	for v := range s {
+		if !other.Contains(v) {
+			out[v] = struct{}{}
+		}
+	}
+	return out
+}
+
+// SymmetricDifference returns the values that are in only one of both sets
+func (s Set[T]) SymmetricDifference(other Set[T]) Set[T] {
+	out := s.Difference(other)
+	for v := range other {
+		if !s.Contains(v) {
+			out[v] = struct{}{}
+		}
+	}
+	return out
+}
+
+// Subset reports whether every value of s is also in other
+func (s Set[T]) Subset(other Set[T]) bool {
+	if len(s) > len(other) {
+		return false
+	}
+	for v := range s {
+		if !other.Contains(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// Sorted returns the values ordered by f, since iterating over a map has a random order
+func (s Set[T]) Sorted(f OrderableFunc[T]) []T {
+	out := make([]T, 0, len(s))
+	for v := range s {
+		out = append(out, v)
+	}
+	slices.SortFunc(out, f)
+	return out
+}
+
+// SortedSet keeps its values ordered in a Tree, so it can be iterated in order and
+// queried by range. Unlike Set, the values don't need to be comparable.
+type SortedSet[T any] struct {
+	t *Tree[T]
+}
+
+func NewSortedSet[T any](f OrderableFunc[T], vals ...T) *SortedSet[T] {
+	s := &SortedSet[T]{t: NewTree(f)}
+	s.Add(vals...)
+	return s
+}
+
+func (s *SortedSet[T]) Add(vals ...T) {
+	for _, v := range vals {
+		s.t.Add(v)
+	}
+}
+
+func (s *SortedSet[T]) Remove(v T) bool {
+	return s.t.Remove(v)
+}
+
+func (s *SortedSet[T]) Contains(v T) bool {
+	return s.t.Contains(v)
+}
+
+func (s *SortedSet[T]) Len() int {
+	return s.t.Len()
+}
+
+func (s *SortedSet[T]) Min() (T, bool) {
+	return s.t.Min()
+}
+
+func (s *SortedSet[T]) Max() (T, bool) {
+	return s.t.Max()
+}
+
+func (s *SortedSet[T]) Floor(v T) (T, bool) {
+	return s.t.Floor(v)
+}
+
+func (s *SortedSet[T]) Ceiling(v T) (T, bool) {
+	return s.t.Ceiling(v)
+}
+
+// Range calls fn in order with the values between lo and hi (both inclusive) until fn returns false
+func (s *SortedSet[T]) Range(lo, hi T, fn func(T) bool) {
+	s.t.Range(lo, hi, fn)
+}
+
+func (s *SortedSet[T]) Walk(fn func(T) bool) {
+	s.t.Walk(fn)
+}
+
+func (s *SortedSet[T]) Iterator() *Iterator[T] {
+	return s.t.Iterator()
+}
+
+func (s *SortedSet[T]) Values() []T {
+	out := make([]T, 0, s.Len())
+	s.Walk(func(v T) bool {
+		out = append(out, v)
+		return true
+	})
+	return out
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	a := NewSet(1, 2, 3, 4)
+	b := NewSet(3, 4, 5)
+	sorted := func(s Set[int]) string {
+		return fmt.Sprint(s.Sorted(BuiltInOrderable[int]))
+	}
+	data := []struct {
+		name     string
+		r        Set[int]
+		expected string
+	}{
+		{"union", a.Union(b), "[1 2 3 4 5]"},
+		{"intersection", a.Intersection(b), "[3 4]"},
+		{"difference", a.Difference(b), "[1 2]"},
+		{"symmetric_difference", a.SymmetricDifference(b), "[1 2 5]"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			if got := sorted(d.r); got != d.expected {
+				t.Errorf("expected %s, got %s", d.expected, got)
+			}
+		})
+	}
+	if !NewSet(3, 4).Subset(a) || b.Subset(a) {
+		t.Error("unexpected Subset result")
+	}
+	a.Remove(1)
+	if a.Contains(1) || a.Len() != 3 {
+		t.Error("expected 1 to be removed")
+	}
+}
+
+func TestSortedSet(t *testing.T) {
+	s := NewSortedSet(Person.Order,
+		Person{"Marla", 34}, Person{"Bob", 47}, Person{"David", 34}, Person{"Fred", 20})
+	s.Add(Person{"Bob", 47})
+	if s.Len() != 4 {
+		t.Errorf("expected 4 people, got %d", s.Len())
+	}
+	var names []string
+	s.Range(Person{"", 30}, Person{"", 40}, func(p Person) bool {
+		names = append(names, p.Name)
+		return true
+	})
+	if got := fmt.Sprint(names); got != "[David Marla]" {
+		t.Errorf("expected the people in their thirties, got %s", got)
+	}
+	if got := fmt.Sprint(s.Values()); got != "[{Fred 20} {David 34} {Marla 34} {Bob 47}]" {
+		t.Errorf("unexpected order %s", got)
+	}
+}
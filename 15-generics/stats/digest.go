@@ -0,0 +1,118 @@
+package stats
+
+import (
+	"math"
+	"slices"
+)
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// Digest estimates percentiles of a stream of values without keeping all of them, using
+// a merging t-digest. Values are grouped into centroids that are small near the ends of
+// the distribution and bigger around the median, so the extreme percentiles (like p99
+// of request latencies) stay accurate. Compression bounds the number of centroids,
+// higher values use more memory and are more accurate.
+type Digest[T Number] struct {
+	compression float64
+	centroids   []centroid
+	buffer      []float64
+	count       float64
+	min, max    float64
+}
+
+func NewDigest[T Number](compression float64) *Digest[T] {
+	return &Digest[T]{
+		compression: max(compression, 20),
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+func (d *Digest[T]) Add(v T) {
+	x := float64(v)
+	d.buffer = append(d.buffer, x)
+	d.count++
+	d.min = math.Min(d.min, x)
+	d.max = math.Max(d.max, x)
+	if len(d.buffer) >= int(5*d.compression) {
+		d.flush()
+	}
+}
+
+func (d *Digest[T]) Count() int {
+	return int(d.count)
+}
+
+// scale maps a quantile to the k scale, where every centroid can span at most 1 unit
+func (d *Digest[T]) scale(q float64) float64 {
+	return d.compression / (2 * math.Pi) * math.Asin(2*q-1)
+}
+
+// flush merges the buffered values into the centroids
+func (d *Digest[T]) flush() {
+	if len(d.buffer) == 0 {
+		return
+	}
+	all := slices.Clone(d.centroids)
+	for _, x := range d.buffer {
+		all = append(all, centroid{mean: x, weight: 1})
+	}
+	d.buffer = d.buffer[:0]
+	slices.SortFunc(all, func(c1, c2 centroid) int {
+		switch {
+		case c1.mean < c2.mean:
+			return -1
+		case c1.mean > c2.mean:
+			return 1
+		}
+		return 0
+	})
+
+	merged := all[:1]
+	cum := 0.0
+	kLeft := d.scale(0)
+	for _, c := range all[1:] {
+		last := &merged[len(merged)-1]
+		if d.scale((cum+last.weight+c.weight)/d.count)-kLeft <= 1 {
+			// Weighted mean of both centroids
+			last.weight += c.weight
+			last.mean += (c.mean - last.mean) * c.weight / last.weight
+			continue
+		}
+		cum += last.weight
+		kLeft = d.scale(cum / d.count)
+		merged = append(merged, c)
+	}
+	d.centroids = merged
+}
+
+// Percentile estimates the p-th percentile (0 to 100) of the values added so far
+func (d *Digest[T]) Percentile(p float64) (float64, error) {
+	d.flush()
+	if d.count == 0 {
+		return 0, ErrEmpty
+	}
+	if p <= 0 {
+		return d.min, nil
+	}
+	if p >= 100 {
+		return d.max, nil
+	}
+	target := p / 100 * d.count
+	// Each centroid is treated as if its values were spread evenly around its mean,
+	// so we interpolate between the centers of neighbouring centroids
+	prevCenter, prevMean := 0.0, d.min
+	cum := 0.0
+	for _, c := range d.centroids {
+		center := cum + c.weight/2
+		if target < center {
+			return prevMean + (target-prevCenter)/(center-prevCenter)*(c.mean-prevMean), nil
+		}
+		prevCenter, prevMean = center, c.mean
+		cum += c.weight
+	}
+	return prevMean + (target-prevCenter)/(d.count-prevCenter)*(d.max-prevMean), nil
+}
@@ -0,0 +1,119 @@
+// Package stats computes summary statistics over slices of any integer or float type
+package stats
+
+import (
+	"errors"
+	"math"
+	"slices"
+)
+
+// Number is like BuiltInOrdered in package main, without strings since they can't be added up
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr | ~float32 | ~float64
+}
+
+var ErrEmpty = errors.New("stats: empty input")
+
+func Sum[T Number](s []T) T {
+	var total T
+	for _, v := range s {
+		total += v
+	}
+	return total
+}
+
+// Mean adds up the values as float64, so large integers don't overflow T
+func Mean[T Number](s []T) (float64, error) {
+	if len(s) == 0 {
+		return 0, ErrEmpty
+	}
+	total := 0.0
+	for _, v := range s {
+		total += float64(v)
+	}
+	return total / float64(len(s)), nil
+}
+
+// Variance is the population variance, the mean of the squared differences from the mean
+func Variance[T Number](s []T) (float64, error) {
+	mean, err := Mean(s)
+	if err != nil {
+		return 0, err
+	}
+	total := 0.0
+	for _, v := range s {
+		d := float64(v) - mean
+		total += d * d
+	}
+	return total / float64(len(s)), nil
+}
+
+func StdDev[T Number](s []T) (float64, error) {
+	v, err := Variance(s)
+	return math.Sqrt(v), err
+}
+
+func Median[T Number](s []T) (float64, error) {
+	return Percentile(s, 50)
+}
+
+// Percentile returns the p-th percentile (0 to 100) interpolating linearly between the
+// closest values. It sorts a copy of s, use a Digest for streams of values.
+func Percentile[T Number](s []T, p float64) (float64, error) {
+	if len(s) == 0 {
+		return 0, ErrEmpty
+	}
+	if p < 0 || p > 100 || math.IsNaN(p) {
+		return 0, errors.New("stats: percentile must be between 0 and 100")
+	}
+	sorted := slices.Clone(s)
+	slices.Sort(sorted)
+	rank := p / 100 * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	frac := rank - float64(lo)
+	return float64(sorted[lo]) + frac*(float64(sorted[hi])-float64(sorted[lo])), nil
+}
+
+// Mode returns the most frequent value, the smallest one if there's a tie
+func Mode[T Number](s []T) (T, error) {
+	if len(s) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	counts := map[T]int{}
+	mode := s[0]
+	for _, v := range s {
+		counts[v]++
+		if c := counts[v]; c > counts[mode] || (c == counts[mode] && v < mode) {
+			mode = v
+		}
+	}
+	return mode, nil
+}
+
+// Histogram counts values into buckets. Bucket i holds the values that are less than or
+// equal to Bounds[i] and greater than Bounds[i-1], the last bucket holds everything
+// greater than the last bound.
+type Histogram[T Number] struct {
+	Bounds []T
+	Counts []int
+}
+
+// NewHistogram sorts the bounds and drops duplicates, so they can be passed in any order
+func NewHistogram[T Number](bounds ...T) *Histogram[T] {
+	b := slices.Clone(bounds)
+	slices.Sort(b)
+	b = slices.Compact(b)
+	return &Histogram[T]{
+		Bounds: b,
+		Counts: make([]int, len(b)+1),
+	}
+}
+
+func (h *Histogram[T]) Add(values ...T) {
+	for _, v := range values {
+		i, _ := slices.BinarySearch(h.Bounds, v)
+		h.Counts[i]++
+	}
+}
@@ -0,0 +1,114 @@
+package stats_test
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dahc36/learning-go/15-generics/stats"
+)
+
+func TestSummary(t *testing.T) {
+	s := []int{2, 4, 4, 4, 5, 5, 7, 9}
+	if sum := stats.Sum(s); sum != 40 {
+		t.Errorf("expected Sum 40, got %d", sum)
+	}
+	data := []struct {
+		name     string
+		fn       func([]int) (float64, error)
+		expected float64
+	}{
+		{"mean", stats.Mean[int], 5},
+		{"variance", stats.Variance[int], 4},
+		{"std_dev", stats.StdDev[int], 2},
+		{"median", stats.Median[int], 4.5},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			r, err := d.fn(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if r != d.expected {
+				t.Errorf("expected %f, got %f", d.expected, r)
+			}
+			if _, err := d.fn(nil); !errors.Is(err, stats.ErrEmpty) {
+				t.Errorf("expected ErrEmpty, got %v", err)
+			}
+		})
+	}
+	if m, _ := stats.Mode(s); m != 4 {
+		t.Errorf("expected Mode 4, got %d", m)
+	}
+	if m, _ := stats.Mode([]float64{3.5, 1.5, 3.5, 1.5}); m != 1.5 {
+		t.Errorf("expected the smallest mode on ties, got %f", m)
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	// Durations have int64 as their underlying type, so they work as well
+	s := []time.Duration{40, 10, 30, 20, 50}
+	for p, expected := range map[float64]float64{0: 10, 25: 20, 50: 30, 90: 46, 100: 50} {
+		r, err := stats.Percentile(s, p)
+		if err != nil || r != expected {
+			t.Errorf("p%v: expected %f, got %f %v", p, expected, r, err)
+		}
+	}
+	if _, err := stats.Percentile(s, 101); err == nil {
+		t.Error("expected an error for p101")
+	}
+}
+
+func TestHistogram(t *testing.T) {
+	h := stats.NewHistogram(100, 10, 50, 10)
+	h.Add(1, 10, 11, 50, 75, 100, 500)
+	if got := fmt.Sprint(h.Bounds, h.Counts); got != "[10 50 100] [2 2 2 1]" {
+		t.Errorf("unexpected histogram %s", got)
+	}
+}
+
+func TestDigest(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	d := stats.NewDigest[float64](100)
+	values := make([]float64, 100000)
+	for i := range values {
+		// Exponential values look like request latencies, with a long tail
+		values[i] = r.ExpFloat64() * 100
+		d.Add(values[i])
+	}
+	slices.Sort(values)
+	if d.Count() != len(values) {
+		t.Errorf("expected Count %d, got %d", len(values), d.Count())
+	}
+	for _, p := range []float64{1, 10, 50, 90, 99, 99.9} {
+		exact, _ := stats.Percentile(values, p)
+		estimate, err := d.Percentile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Compare the rank of the estimate, since the tail values are very spread out
+		i, _ := slices.BinarySearch(values, estimate)
+		rank := float64(i) / float64(len(values)) * 100
+		if math.Abs(rank-p) > 0.5 {
+			t.Errorf("p%v: expected around %f, got %f (p%.2f)", p, exact, estimate, rank)
+		}
+	}
+	if _, err := stats.NewDigest[int](100).Percentile(50); !errors.Is(err, stats.ErrEmpty) {
+		t.Errorf("expected ErrEmpty, got %v", err)
+	}
+}
+
+var blackHole float64
+
+func BenchmarkDigest(b *testing.B) {
+	r := rand.New(rand.NewSource(1))
+	d := stats.NewDigest[float64](100)
+	for i := 0; i < b.N; i++ {
+		d.Add(r.ExpFloat64())
+	}
+	blackHole, _ = d.Percentile(99)
+}
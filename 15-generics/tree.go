@@ -2,22 +2,30 @@ package main
 
 type OrderableFunc[T any] func(v1, v2 T) int
 
+// Node is a node of an AVL tree, a binary search tree that keeps the heights of the
+// left and right subtrees of every node within one of each other. That keeps the
+// height of the tree at O(log n) even when the values are added in order.
+// Each node also tracks the number of nodes in its subtree.
 type Node[T any] struct {
 	value       T
 	left, right *Node[T]
+	height      int
+	size        int
 }
 
 func (n *Node[T]) Add(f OrderableFunc[T], v T) *Node[T] {
 	if n == nil {
-		return &Node[T]{value: v}
+		return &Node[T]{value: v, height: 1, size: 1}
 	}
 	switch r := f(v, n.value); {
 	case r <= -1:
 		n.left = n.left.Add(f, v)
 	case r >= 1:
 		n.right = n.right.Add(f, v)
+	default:
+		return n
 	}
-	return n
+	return n.rebalance()
 }
 
 func (n *Node[T]) Contains(f OrderableFunc[T], v T) bool {
@@ -33,6 +41,276 @@ func (n *Node[T]) Contains(f OrderableFunc[T], v T) bool {
 	return true
 }
 
+// find returns the node holding v, or nil
+func (n *Node[T]) find(f OrderableFunc[T], v T) *Node[T] {
+	for n != nil {
+		switch r := f(v, n.value); {
+		case r <= -1:
+			n = n.left
+		case r >= 1:
+			n = n.right
+		default:
+			return n
+		}
+	}
+	return nil
+}
+
+// Floor returns the largest value that's less than or equal to v
+func (n *Node[T]) Floor(f OrderableFunc[T], v T) (T, bool) {
+	var out T
+	var found bool
+	for n != nil {
+		r := f(v, n.value)
+		if r == 0 {
+			return n.value, true
+		}
+		if r < 0 {
+			n = n.left
+		} else {
+			out, found = n.value, true
+			n = n.right
+		}
+	}
+	return out, found
+}
+
+// Ceiling returns the smallest value that's greater than or equal to v
+func (n *Node[T]) Ceiling(f OrderableFunc[T], v T) (T, bool) {
+	var out T
+	var found bool
+	for n != nil {
+		r := f(v, n.value)
+		if r == 0 {
+			return n.value, true
+		}
+		if r > 0 {
+			n = n.right
+		} else {
+			out, found = n.value, true
+			n = n.left
+		}
+	}
+	return out, found
+}
+
+// Range calls fn in order with every value between lo and hi (both inclusive) until
+// fn returns false. Subtrees outside of the range are skipped.
+func (n *Node[T]) Range(f OrderableFunc[T], lo, hi T, fn func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	aboveLo := f(n.value, lo) >= 0
+	belowHi := f(n.value, hi) <= 0
+	if aboveLo && !n.left.Range(f, lo, hi, fn) {
+		return false
+	}
+	if aboveLo && belowHi && !fn(n.value) {
+		return false
+	}
+	if belowHi {
+		return n.right.Range(f, lo, hi, fn)
+	}
+	return true
+}
+
+// Rank returns the number of values that are less than v
+func (n *Node[T]) Rank(f OrderableFunc[T], v T) int {
+	rank := 0
+	for n != nil {
+		if f(v, n.value) <= 0 {
+			n = n.left
+		} else {
+			rank += n.left.getSize() + 1
+			n = n.right
+		}
+	}
+	return rank
+}
+
+// Select returns the value at position i (starting at 0) in sorted order
+func (n *Node[T]) Select(i int) (T, bool) {
+	for n != nil {
+		leftSize := n.left.getSize()
+		switch {
+		case i < leftSize:
+			n = n.left
+		case i > leftSize:
+			i -= leftSize + 1
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// Remove returns the new root of the subtree and whether v was found
+func (n *Node[T]) Remove(f OrderableFunc[T], v T) (*Node[T], bool) {
+	if n == nil {
+		return nil, false
+	}
+	var removed bool
+	switch r := f(v, n.value); {
+	case r <= -1:
+		n.left, removed = n.left.Remove(f, v)
+	case r >= 1:
+		n.right, removed = n.right.Remove(f, v)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// With two children, the smallest value on the right takes the place of the removed one
+		n.value, _ = n.right.Min()
+		n.right = n.right.removeMin()
+		removed = true
+	}
+	if !removed {
+		return n, false
+	}
+	return n.rebalance(), true
+}
+
+func (n *Node[T]) removeMin() *Node[T] {
+	if n.left == nil {
+		return n.right
+	}
+	n.left = n.left.removeMin()
+	return n.rebalance()
+}
+
+func (n *Node[T]) Min() (T, bool) {
+	if n == nil {
+		var zero T
+		return zero, false
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return n.value, true
+}
+
+func (n *Node[T]) Max() (T, bool) {
+	if n == nil {
+		var zero T
+		return zero, false
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return n.value, true
+}
+
+// Walk calls fn with every value in order until fn returns false.
+// It returns false if the walk was stopped early.
+func (n *Node[T]) Walk(fn func(T) bool) bool {
+	if n == nil {
+		return true
+	}
+	return n.left.Walk(fn) && fn(n.value) && n.right.Walk(fn)
+}
+
+// Iterator is a pull-style in-order iterator, call Next until it returns false:
+//
+//	it := t.Iterator()
+//	for v, ok := it.Next(); ok; v, ok = it.Next() {
+//		fmt.Println(v)
+//	}
+//
+// The tree must not be modified while iterating.
+type Iterator[T any] struct {
+	stack []*Node[T]
+}
+
+func (n *Node[T]) Iterator() *Iterator[T] {
+	it := &Iterator[T]{}
+	it.pushLeft(n)
+	return it
+}
+
+func (it *Iterator[T]) pushLeft(n *Node[T]) {
+	for ; n != nil; n = n.left {
+		it.stack = append(it.stack, n)
+	}
+}
+
+func (it *Iterator[T]) Next() (T, bool) {
+	if len(it.stack) == 0 {
+		var zero T
+		return zero, false
+	}
+	n := it.stack[len(it.stack)-1]
+	it.stack = it.stack[:len(it.stack)-1]
+	it.pushLeft(n.right)
+	return n.value, true
+}
+
+// Methods can be called on nil pointers, which lets us treat empty subtrees as height 0
+func (n *Node[T]) getHeight() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *Node[T]) getSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+func (n *Node[T]) update() {
+	n.height = 1 + max(n.left.getHeight(), n.right.getHeight())
+	n.size = 1 + n.left.getSize() + n.right.getSize()
+}
+
+func (n *Node[T]) balance() int {
+	return n.left.getHeight() - n.right.getHeight()
+}
+
+func (n *Node[T]) rotateRight() *Node[T] {
+	l := n.left
+	n.left = l.right
+	l.right = n
+	n.update()
+	l.update()
+	return l
+}
+
+func (n *Node[T]) rotateLeft() *Node[T] {
+	r := n.right
+	n.right = r.left
+	r.left = n
+	n.update()
+	r.update()
+	return r
+}
+
+// rebalance fixes the height of n after one of its subtrees changed and rotates
+// it if the subtrees' heights differ by more than one. It returns the new root
+// of the subtree.
+func (n *Node[T]) rebalance() *Node[T] {
+	n.update()
+	switch b := n.balance(); {
+	case b > 1:
+		if n.left.balance() < 0 {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case b < -1:
+		if n.right.balance() > 0 {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+	return n
+}
+
 type Tree[T any] struct {
 	f    OrderableFunc[T]
 	root *Node[T]
@@ -46,8 +324,73 @@ func (t *Tree[T]) Contains(v T) bool {
 	return t.root.Contains(t.f, v)
 }
 
+func (t *Tree[T]) Remove(v T) bool {
+	var removed bool
+	t.root, removed = t.root.Remove(t.f, v)
+	return removed
+}
+
+func (t *Tree[T]) Len() int {
+	return t.root.getSize()
+}
+
+func (t *Tree[T]) Min() (T, bool) {
+	return t.root.Min()
+}
+
+func (t *Tree[T]) Max() (T, bool) {
+	return t.root.Max()
+}
+
+func (t *Tree[T]) Floor(v T) (T, bool) {
+	return t.root.Floor(t.f, v)
+}
+
+func (t *Tree[T]) Ceiling(v T) (T, bool) {
+	return t.root.Ceiling(t.f, v)
+}
+
+func (t *Tree[T]) Range(lo, hi T, fn func(T) bool) {
+	t.root.Range(t.f, lo, hi, fn)
+}
+
+func (t *Tree[T]) Rank(v T) int {
+	return t.root.Rank(t.f, v)
+}
+
+func (t *Tree[T]) Select(i int) (T, bool) {
+	return t.root.Select(i)
+}
+
+func (t *Tree[T]) Walk(fn func(T) bool) {
+	t.root.Walk(fn)
+}
+
+func (t *Tree[T]) Iterator() *Iterator[T] {
+	return t.root.Iterator()
+}
+
 func NewTree[T any](f OrderableFunc[T]) *Tree[T] {
 	return &Tree[T]{
 		f: f,
 	}
 }
+
+// Orderer is satisfied by types that know how to compare themselves, like Person
+type Orderer[T any] interface {
+	Order(T) int
+}
+
+// NewOrderedTree uses the Order method of T, so NewOrderedTree[Person]() is the same
+// as NewTree(Person.Order)
+func NewOrderedTree[T Orderer[T]]() *Tree[T] {
+	return NewTree(func(v1, v2 T) int {
+		return v1.Order(v2)
+	})
+}
+
+// NewNaturalTree uses the < and > operators, so NewNaturalTree[int]() is the same
+// as NewTree(BuiltInOrderable[int])
+func NewNaturalTree[T BuiltInOrdered]() *Tree[T] {
+	return NewTree(BuiltInOrderable[T])
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+func TestTreeBalanced(t *testing.T) {
+	tree := NewTree(BuiltInOrderable[int])
+	n := 10000
+	for i := 0; i < n; i++ {
+		tree.Add(i)
+	}
+	// An AVL tree is never taller than ~1.44 * log2(n)
+	maxHeight := int(1.44*math.Log2(float64(n))) + 1
+	if h := tree.root.getHeight(); h > maxHeight {
+		t.Errorf("expected height <= %d, got %d", maxHeight, h)
+	}
+	for i := 0; i < n; i++ {
+		if !tree.Contains(i) {
+			t.Fatalf("expected tree to contain %d", i)
+		}
+	}
+	if tree.Contains(-1) || tree.Contains(n) {
+		t.Error("tree contains values that were never added")
+	}
+}
+
+// unbalancedAdd is the plain binary search tree insert, kept to compare against
+func unbalancedAdd[T any](n *Node[T], f OrderableFunc[T], v T) *Node[T] {
+	if n == nil {
+		return &Node[T]{value: v}
+	}
+	switch r := f(v, n.value); {
+	case r <= -1:
+		n.left = unbalancedAdd(n.left, f, v)
+	case r >= 1:
+		n.right = unbalancedAdd(n.right, f, v)
+	}
+	return n
+}
+
+var blackHole bool
+
+func BenchmarkTree(b *testing.B) {
+	f := BuiltInOrderable[int]
+	for _, size := range []int{1000, 10000, 1000000} {
+		inputs := map[string][]int{
+			"sorted": make([]int, size),
+			"random": rand.New(rand.NewSource(1)).Perm(size),
+		}
+		for i := range inputs["sorted"] {
+			inputs["sorted"][i] = i
+		}
+		for _, order := range []string{"sorted", "random"} {
+			values := inputs[order]
+			b.Run(fmt.Sprintf("AVL-%s-%d", order, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					var root *Node[int]
+					for _, v := range values {
+						root = root.Add(f, v)
+					}
+					blackHole = root.Contains(f, size-1)
+				}
+			})
+			b.Run(fmt.Sprintf("Unbalanced-%s-%d", order, size), func(b *testing.B) {
+				if order == "sorted" && size > 10000 {
+					// Every insert walks the whole list, this would take hours
+					b.Skip("quadratic for sorted input")
+				}
+				for i := 0; i < b.N; i++ {
+					var root *Node[int]
+					for _, v := range values {
+						root = unbalancedAdd(root, f, v)
+					}
+					blackHole = root.Contains(f, size-1)
+				}
+			})
+		}
+	}
+}
+
+// checkNode verifies the ordering, AVL balance and sizes of every node
+func checkNode[T any](t *testing.T, f OrderableFunc[T], n *Node[T]) {
+	t.Helper()
+	if n == nil {
+		return
+	}
+	if b := n.balance(); b < -1 || b > 1 {
+		t.Fatalf("node %v is unbalanced: %d", n.value, b)
+	}
+	if n.size != 1+n.left.getSize()+n.right.getSize() {
+		t.Fatalf("node %v has the wrong size %d", n.value, n.size)
+	}
+	if n.left != nil && f(n.left.value, n.value) >= 0 {
+		t.Fatalf("left child %v not smaller than %v", n.left.value, n.value)
+	}
+	if n.right != nil && f(n.right.value, n.value) <= 0 {
+		t.Fatalf("right child %v not bigger than %v", n.right.value, n.value)
+	}
+	checkNode(t, f, n.left)
+	checkNode(t, f, n.right)
+}
+
+func TestTreeRemove(t *testing.T) {
+	tree := NewTree(BuiltInOrderable[int])
+	r := rand.New(rand.NewSource(1))
+	present := map[int]bool{}
+	for i := 0; i < 5000; i++ {
+		v := r.Intn(1000)
+		if r.Intn(3) == 0 {
+			if removed := tree.Remove(v); removed != present[v] {
+				t.Fatalf("Remove(%d): expected %v, got %v", v, present[v], removed)
+			}
+			delete(present, v)
+		} else {
+			tree.Add(v)
+			present[v] = true
+		}
+		if tree.Len() != len(present) {
+			t.Fatalf("expected Len %d, got %d", len(present), tree.Len())
+		}
+	}
+	checkNode(t, tree.f, tree.root)
+	for v := 0; v < 1000; v++ {
+		if tree.Contains(v) != present[v] {
+			t.Errorf("Contains(%d): expected %v", v, present[v])
+		}
+	}
+}
+
+func TestTreeOrdered(t *testing.T) {
+	tree := NewTree(BuiltInOrderable[int])
+	if _, ok := tree.Min(); ok {
+		t.Error("expected no Min in an empty tree")
+	}
+	if _, ok := tree.Max(); ok {
+		t.Error("expected no Max in an empty tree")
+	}
+	for _, v := range []int{50, 20, 80, 10, 30, 70, 90, 30} {
+		tree.Add(v)
+	}
+	if v, _ := tree.Min(); v != 10 {
+		t.Errorf("expected Min 10, got %d", v)
+	}
+	if v, _ := tree.Max(); v != 90 {
+		t.Errorf("expected Max 90, got %d", v)
+	}
+
+	var walked []int
+	tree.Walk(func(v int) bool {
+		walked = append(walked, v)
+		return v < 50
+	})
+	if got := fmt.Sprint(walked); got != "[10 20 30 50]" {
+		t.Errorf("expected Walk to stop after 50, got %s", got)
+	}
+
+	var iterated []int
+	it := tree.Iterator()
+	for v, ok := it.Next(); ok; v, ok = it.Next() {
+		iterated = append(iterated, v)
+	}
+	if got := fmt.Sprint(iterated); got != "[10 20 30 50 70 80 90]" {
+		t.Errorf("expected all values in order, got %s", got)
+	}
+}
+
+func TestConstrainedTrees(t *testing.T) {
+	people := NewOrderedTree[Person]()
+	people.Add(Person{"Marla", 34})
+	people.Add(Person{"Bob", 47})
+	people.Add(Person{"David", 34})
+	if p, _ := people.Min(); p.Name != "David" {
+		t.Errorf("expected David to be the youngest, got %v", p)
+	}
+
+	type myInt int
+	nums := NewNaturalTree[myInt]()
+	nums.Add(3)
+	nums.Add(1)
+	nums.Add(2)
+	if v, _ := nums.Select(1); v != 2 {
+		t.Errorf("expected 2, got %d", v)
+	}
+}
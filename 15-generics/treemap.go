@@ -0,0 +1,78 @@
+package main
+
+type entry[K, V any] struct {
+	key   K
+	value V
+}
+
+// TreeMap is an ordered map, its entries are stored in a Tree and only the
+// keys are compared with the OrderableFunc
+type TreeMap[K, V any] struct {
+	f    OrderableFunc[entry[K, V]]
+	root *Node[entry[K, V]]
+}
+
+func NewTreeMap[K, V any](f OrderableFunc[K]) *TreeMap[K, V] {
+	return &TreeMap[K, V]{
+		f: func(e1, e2 entry[K, V]) int {
+			return f(e1.key, e2.key)
+		},
+	}
+}
+
+func (m *TreeMap[K, V]) Put(k K, v V) {
+	if n := m.root.find(m.f, entry[K, V]{key: k}); n != nil {
+		n.value.value = v
+		return
+	}
+	m.root = m.root.Add(m.f, entry[K, V]{key: k, value: v})
+}
+
+func (m *TreeMap[K, V]) Get(k K) (V, bool) {
+	if n := m.root.find(m.f, entry[K, V]{key: k}); n != nil {
+		return n.value.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+func (m *TreeMap[K, V]) Delete(k K) bool {
+	var removed bool
+	m.root, removed = m.root.Remove(m.f, entry[K, V]{key: k})
+	return removed
+}
+
+func (m *TreeMap[K, V]) Len() int {
+	return m.root.getSize()
+}
+
+// Floor returns the entry with the largest key that's less than or equal to k
+func (m *TreeMap[K, V]) Floor(k K) (K, V, bool) {
+	e, ok := m.root.Floor(m.f, entry[K, V]{key: k})
+	return e.key, e.value, ok
+}
+
+// Ceiling returns the entry with the smallest key that's greater than or equal to k
+func (m *TreeMap[K, V]) Ceiling(k K) (K, V, bool) {
+	e, ok := m.root.Ceiling(m.f, entry[K, V]{key: k})
+	return e.key, e.value, ok
+}
+
+// Range calls fn in key order for every entry with a key between lo and hi (both inclusive)
+// until fn returns false
+func (m *TreeMap[K, V]) Range(lo, hi K, fn func(K, V) bool) {
+	m.root.Range(m.f, entry[K, V]{key: lo}, entry[K, V]{key: hi}, func(e entry[K, V]) bool {
+		return fn(e.key, e.value)
+	})
+}
+
+// Rank returns the number of keys that are less than k
+func (m *TreeMap[K, V]) Rank(k K) int {
+	return m.root.Rank(m.f, entry[K, V]{key: k})
+}
+
+// Select returns the entry at position i (starting at 0) in key order
+func (m *TreeMap[K, V]) Select(i int) (K, V, bool) {
+	e, ok := m.root.Select(i)
+	return e.key, e.value, ok
+}
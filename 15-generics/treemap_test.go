@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestTreeMap(t *testing.T) {
+	m := NewTreeMap[int, string](BuiltInOrderable[int])
+	for _, k := range []int{40, 10, 30, 20, 50} {
+		m.Put(k, fmt.Sprint("v", k))
+	}
+	m.Put(30, "thirty")
+	if m.Len() != 5 {
+		t.Errorf("expected Len 5, got %d", m.Len())
+	}
+	if v, ok := m.Get(30); !ok || v != "thirty" {
+		t.Errorf("expected Get(30) to be thirty, got %s", v)
+	}
+	if _, ok := m.Get(35); ok {
+		t.Error("expected Get(35) to be missing")
+	}
+
+	data := []struct {
+		name  string
+		fn    func(int) (int, string, bool)
+		key   int
+		found bool
+		out   int
+	}{
+		{"floor_exact", m.Floor, 30, true, 30},
+		{"floor_between", m.Floor, 35, true, 30},
+		{"floor_below_min", m.Floor, 5, false, 0},
+		{"ceiling_exact", m.Ceiling, 30, true, 30},
+		{"ceiling_between", m.Ceiling, 35, true, 40},
+		{"ceiling_above_max", m.Ceiling, 55, false, 0},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			k, _, ok := d.fn(d.key)
+			if ok != d.found || k != d.out {
+				t.Errorf("expected (%d, %v), got (%d, %v)", d.out, d.found, k, ok)
+			}
+		})
+	}
+
+	var keys []int
+	m.Range(15, 45, func(k int, v string) bool {
+		keys = append(keys, k)
+		return true
+	})
+	if got := fmt.Sprint(keys); got != "[20 30 40]" {
+		t.Errorf("expected Range keys [20 30 40], got %s", got)
+	}
+
+	for i, k := range []int{10, 20, 30, 40, 50} {
+		if r := m.Rank(k); r != i {
+			t.Errorf("expected Rank(%d) to be %d, got %d", k, i, r)
+		}
+		if s, _, _ := m.Select(i); s != k {
+			t.Errorf("expected Select(%d) to be %d, got %d", i, k, s)
+		}
+	}
+	if _, _, ok := m.Select(5); ok {
+		t.Error("expected Select(5) to be out of range")
+	}
+
+	if !m.Delete(30) || m.Delete(30) {
+		t.Error("expected Delete(30) to succeed only once")
+	}
+	if r := m.Rank(40); r != 2 {
+		t.Errorf("expected Rank(40) after delete to be 2, got %d", r)
+	}
+}
+
+func TestTreeMapPersonKeys(t *testing.T) {
+	m := NewTreeMap[Person, int](Person.Order)
+	m.Put(Person{"Bob", 47}, 1)
+	m.Put(Person{"David", 34}, 2)
+	m.Put(Person{"Marla", 34}, 3)
+	k, v, ok := m.Floor(Person{"Zed", 40})
+	if !ok || k.Name != "Marla" || v != 3 {
+		t.Errorf("expected Marla, got %v %d %v", k, v, ok)
+	}
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Metric is a strategy for measuring the distance between two vectors of the same length
+type Metric func(v1, v2 []float64) float64
+
+func Euclidean(v1, v2 []float64) float64 {
+	total := 0.0
+	for i := range v1 {
+		d := v1[i] - v2[i]
+		total += d * d
+	}
+	return math.Sqrt(total)
+}
+
+func Manhattan(v1, v2 []float64) float64 {
+	total := 0.0
+	for i := range v1 {
+		total += math.Abs(v1[i] - v2[i])
+	}
+	return total
+}
+
+func Chebyshev(v1, v2 []float64) float64 {
+	out := 0.0
+	for i := range v1 {
+		out = math.Max(out, math.Abs(v1[i]-v2[i]))
+	}
+	return out
+}
+
+// Cosine is 1 minus the cosine of the angle between both vectors, it only looks at their
+// direction. It isn't a real distance, so it can't be used to search a KDTree.
+func Cosine(v1, v2 []float64) float64 {
+	dot, n1, n2 := 0.0, 0.0, 0.0
+	for i := range v1 {
+		dot += v1[i] * v2[i]
+		n1 += v1[i] * v1[i]
+		n2 += v2[i] * v2[i]
+	}
+	if n1 == 0 || n2 == 0 {
+		return 1
+	}
+	return 1 - dot/math.Sqrt(n1*n2)
+}
+
+// PointN is a point or vector with any number of coordinates. Go can't use a number
+// as a type parameter, so the coordinates are stored in a slice instead of an array.
+// Operations between points with a different number of coordinates panic.
+// PointN satisfies Differ and Spatial, measuring distances with its Metric.
+type PointN struct {
+	coords []float64
+	metric Metric
+}
+
+// NewPointN copies the coordinates, a nil metric means Euclidean
+func NewPointN(metric Metric, coords ...float64) PointN {
+	if metric == nil {
+		metric = Euclidean
+	}
+	return PointN{
+		coords: append([]float64(nil), coords...),
+		metric: metric,
+	}
+}
+
+func (p Point2D) Vec() PointN {
+	return NewPointN(Euclidean, p.X, p.Y)
+}
+
+func (p Point3D) Vec() PointN {
+	return NewPointN(Euclidean, p.X, p.Y, p.Z)
+}
+
+// WithMetric returns the same point measuring distances with another metric
+func (p PointN) WithMetric(metric Metric) PointN {
+	return NewPointN(metric, p.coords...)
+}
+
+func (p PointN) String() string {
+	parts := make([]string, len(p.coords))
+	for i, c := range p.coords {
+		parts[i] = fmt.Sprintf("%f", c)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func (p PointN) Diff(from PointN) float64 {
+	p.mustMatch(from)
+	return p.metric(p.coords, from.coords)
+}
+
+func (p PointN) Dims() int {
+	return len(p.coords)
+}
+
+func (p PointN) Coord(i int) float64 {
+	return p.coords[i]
+}
+
+func (p PointN) mustMatch(other PointN) {
+	if len(p.coords) != len(other.coords) {
+		panic(fmt.Sprintf("points have different dimensions: %d and %d", len(p.coords), len(other.coords)))
+	}
+}
+
+// combine builds a new point applying f to each pair of coordinates
+func (p PointN) combine(other PointN, f func(c1, c2 float64) float64) PointN {
+	p.mustMatch(other)
+	out := make([]float64, len(p.coords))
+	for i := range out {
+		out[i] = f(p.coords[i], other.coords[i])
+	}
+	return PointN{coords: out, metric: p.metric}
+}
+
+func (p PointN) Add(other PointN) PointN {
+	return p.combine(other, func(c1, c2 float64) float64 { return c1 + c2 })
+}
+
+func (p PointN) Sub(other PointN) PointN {
+	return p.combine(other, func(c1, c2 float64) float64 { return c1 - c2 })
+}
+
+func (p PointN) Scale(s float64) PointN {
+	return p.combine(p, func(c, _ float64) float64 { return c * s })
+}
+
+func (p PointN) Dot(other PointN) float64 {
+	p.mustMatch(other)
+	total := 0.0
+	for i := range p.coords {
+		total += p.coords[i] * other.coords[i]
+	}
+	return total
+}
+
+func (p PointN) Norm() float64 {
+	return math.Sqrt(p.Dot(p))
+}
+
+// Normalize returns the vector with the same direction and length 1, the zero vector stays as is
+func (p PointN) Normalize() PointN {
+	n := p.Norm()
+	if n == 0 {
+		return p.Scale(1)
+	}
+	return p.Scale(1 / n)
+}
+
+// Cross is the cross product, it's only defined for 3 dimensions
+func (p PointN) Cross(other PointN) PointN {
+	p.mustMatch(other)
+	if len(p.coords) != 3 {
+		panic("cross product needs 3 dimensions")
+	}
+	a, b := p.coords, other.coords
+	return NewPointN(p.metric, a[1]*b[2]-a[2]*b[1], a[2]*b[0]-a[0]*b[2], a[0]*b[1]-a[1]*b[0])
+}
+
+// Centroid is the average of all the points, it returns false if there are none
+func Centroid(points ...PointN) (PointN, bool) {
+	if len(points) == 0 {
+		return PointN{}, false
+	}
+	sum := points[0].Scale(1)
+	for _, p := range points[1:] {
+		sum = sum.Add(p)
+	}
+	return sum.Scale(1 / float64(len(points))), true
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	p1 := NewPointN(nil, 1, 2)
+	p2 := NewPointN(nil, 4, 6)
+	data := []struct {
+		name     string
+		metric   Metric
+		expected float64
+	}{
+		{"euclidean", Euclidean, 5},
+		{"manhattan", Manhattan, 7},
+		{"chebyshev", Chebyshev, 4},
+		{"cosine", Cosine, 1 - 16/math.Sqrt(5*52)},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			if r := p1.WithMetric(d.metric).Diff(p2); math.Abs(r-d.expected) > 1e-12 {
+				t.Errorf("expected %f, got %f", d.expected, r)
+			}
+		})
+	}
+	if r := (Point2D{1, 2}).Diff(Point2D{4, 6}); r != p1.Diff(p2) {
+		t.Errorf("expected Point2D and PointN to agree, got %f", r)
+	}
+}
+
+func TestVectorOperations(t *testing.T) {
+	x := NewPointN(nil, 1, 0, 0)
+	y := NewPointN(nil, 0, 1, 0)
+	data := []struct {
+		name     string
+		r        PointN
+		expected string
+	}{
+		{"add", x.Add(y), "{1.000000,1.000000,0.000000}"},
+		{"sub", x.Sub(y), "{1.000000,-1.000000,0.000000}"},
+		{"scale", x.Scale(3), "{3.000000,0.000000,0.000000}"},
+		{"cross", x.Cross(y), "{0.000000,0.000000,1.000000}"},
+		{"normalize", NewPointN(nil, 3, 4).Normalize(), "{0.600000,0.800000}"},
+		{"from_point3d", (Point3D{1, 2, 3}).Vec(), "{1.000000,2.000000,3.000000}"},
+	}
+	for _, d := range data {
+		t.Run(d.name, func(t *testing.T) {
+			if got := d.r.String(); got != d.expected {
+				t.Errorf("expected %s, got %s", d.expected, got)
+			}
+		})
+	}
+	if x.Dot(y) != 0 {
+		t.Error("expected orthogonal vectors")
+	}
+	c, _ := Centroid(NewPointN(nil, 0, 0), NewPointN(nil, 2, 0), NewPointN(nil, 1, 3))
+	if c.String() != "{1.000000,1.000000}" {
+		t.Errorf("unexpected centroid %s", c)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for different dimensions")
+		}
+	}()
+	x.Add(NewPointN(nil, 1, 2))
+}
+
+func TestPointNWithGenericCode(t *testing.T) {
+	origin := NewPointN(Manhattan, 0, 0, 0, 0)
+	a := Pair[PointN]{origin, NewPointN(Manhattan, 1, 1, 1, 1)}
+	b := Pair[PointN]{origin, NewPointN(Manhattan, 3, 0, 0, 0)}
+	if r := FindCloserPair(a, b); r.Val2.Coord(0) != 3 {
+		t.Errorf("expected the Manhattan distance to pick %v, got %v", b, r)
+	}
+	tree := NewKDTree([]PointN{a.Val2, b.Val2, NewPointN(Manhattan, 0, 0, 0, 1)})
+	if n := tree.Nearest(origin, 1); n[0].Coord(3) != 1 {
+		t.Errorf("unexpected nearest point %v", n)
+	}
+}